@@ -0,0 +1,506 @@
+/*
+File: doctor.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-17 23:20:34
+
+Description: 子命令 'doctor' 的实现
+*/
+
+package cli
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gookit/color"
+	"github.com/yhyj/manager/general"
+)
+
+// 诊断结果级别
+const (
+	diagnosisOk    = iota // 正常
+	diagnosisWarn         // 警告，部分功能不可用
+	diagnosisError        // 错误，安装/更新会失败
+)
+
+// diagnosis 单项诊断结果
+type diagnosis struct {
+	Level  int    // 诊断结果级别
+	Item   string // 诊断项
+	Detail string // 诊断详情
+	Remedy string // 修复建议
+}
+
+// Doctor 检查运行环境以及记账文件与磁盘的一致性，并给出修复建议
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//
+// 返回：
+//   - 错误级别的诊断项数
+func Doctor(config *general.Config) int {
+	// 按类别收集诊断结果
+	sections := []struct {
+		title   string
+		results []diagnosis
+	}{
+		{"Dependencies", diagnoseDependencies(config)},
+		{"Paths", diagnosePaths(config)},
+		{"Proxies", diagnoseProxies(config)},
+		{"Leftovers", diagnoseLeftovers(config)},
+		{"Pocket files", diagnoseLedgers(config)},
+	}
+
+	// 输出诊断结果
+	errorNum, warnNum := 0, 0
+	for _, section := range sections {
+		color.Info.Tips("%s", general.FgCyanText(section.title))
+		color.Printf("%s\n", strings.Repeat(general.Separator1st, general.SeparatorBaseLength))
+		for _, result := range section.results {
+			statusFlag := general.SuccessFlag
+			switch result.Level {
+			case diagnosisWarn:
+				statusFlag = general.WarningFlag
+				warnNum++
+			case diagnosisError:
+				statusFlag = general.ErrorFlag
+				errorNum++
+			}
+			color.Printf("%s %s %s\n", statusFlag, general.FgGreenText(result.Item), result.Detail)
+			if result.Remedy != "" {
+				color.Printf("%s %s\n", general.SecondaryText(general.Separator3st), general.NoteText(result.Remedy))
+			}
+		}
+		color.Println()
+	}
+
+	// 诊断汇总
+	if errorNum == 0 && warnNum == 0 {
+		color.Printf("%s %s\n", general.LatestFlag, general.SuccessText("Everything looks fine"))
+	} else {
+		color.Printf("%s %d error(s), %d warning(s)\n", general.WarningFlag, errorNum, warnNum)
+	}
+
+	return errorNum
+}
+
+// diagnoseDependencies 检查安装/更新和配置程序所需的外部命令
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//
+// 返回：
+//   - 诊断结果
+func diagnoseDependencies(config *general.Config) []diagnosis {
+	results := make([]diagnosis, 0)
+
+	sourceMethod := strings.ToLower(config.Program.Method) == "source"
+	dependencies := []struct {
+		command  string // 命令
+		usage    string // 用途
+		required bool   // 缺失时安装/更新是否会失败
+	}{
+		{"git", "compare shell script hashes", len(config.Program.Shell.Names) > 0},
+		{"go", "build source installs", sourceMethod},
+		{"make", "build and install source installs that ship a Makefile", false},
+		{"xz", "extract .tar.xz release assets", false},
+		{"zstd", "extract .tar.zst release assets", false},
+		{general.ChezmoiDependencies, "setup --chezmoi", false},
+		{general.CobraDependencies, "setup --cobra", false},
+		{general.DockerDependencies, "setup --docker", false},
+		{general.FrpcDependencies, "setup --frpc", false},
+		{general.GitDependencies, "setup --git", false},
+		{general.GolangDependencies, "setup --go", false},
+		{general.PipDependencies, "setup --pip", false},
+		{general.UpdateCheckerDependencies, "setup --update-checker", false},
+	}
+
+	// 合并同一命令的多个用途
+	checked := make([]string, 0)
+	usages := make(map[string][]string)
+	required := make(map[string]bool)
+	for _, dependency := range dependencies {
+		if !slices.Contains(checked, dependency.command) {
+			checked = append(checked, dependency.command)
+		}
+		usages[dependency.command] = append(usages[dependency.command], dependency.usage)
+		required[dependency.command] = required[dependency.command] || dependency.required
+	}
+
+	for _, command := range checked {
+		if path, err := exec.LookPath(command); err == nil {
+			results = append(results, diagnosis{Level: diagnosisOk, Item: command, Detail: general.SecondaryText(path)})
+			continue
+		}
+		level := diagnosisWarn
+		if required[command] {
+			level = diagnosisError
+		}
+		results = append(results, diagnosis{
+			Level:  level,
+			Item:   command,
+			Detail: color.Sprintf("not found in PATH, needed to %s", strings.Join(usages[command], ", ")),
+			Remedy: color.Sprintf("Install %s or add it to PATH", command),
+		})
+	}
+
+	return results
+}
+
+// diagnosePaths 检查程序、资源、记账和临时文件夹以及自动补全脚本文件夹是否可写
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//
+// 返回：
+//   - 诊断结果
+func diagnosePaths(config *general.Config) []diagnosis {
+	results := make([]diagnosis, 0)
+
+	paths := []struct {
+		name string // 配置项
+		path string // 路径
+	}{
+		{"program_path", config.Program.ProgramPath},
+		{"resources_path", config.Program.ResourcesPath},
+		{"pocket_path", config.Program.PocketPath},
+		{"cache_path", config.Program.CachePath},
+		{"release_temp", config.Program.ReleaseTemp},
+		{"source_temp", config.Program.SourceTemp},
+	}
+	for _, item := range paths {
+		if item.path == "" { // 当前平台不使用该配置项
+			continue
+		}
+		if err := checkWritable(item.path); err != nil {
+			results = append(results, diagnosis{
+				Level:  diagnosisError,
+				Item:   item.name,
+				Detail: color.Sprintf("%s is not writable: %s", item.path, err),
+				Remedy: color.Sprintf("Run manager with sufficient privileges or point '%s' to a writable directory", item.name),
+			})
+			continue
+		}
+		results = append(results, diagnosis{Level: diagnosisOk, Item: item.name, Detail: general.SecondaryText(item.path)})
+	}
+
+	// 至少需要一个存在且可写的自动补全脚本文件夹
+	completionDirs := slices.Clone(config.Program.Go.CompletionDir)
+	for _, dir := range config.Program.Self.CompletionDir {
+		if !slices.Contains(completionDirs, dir) {
+			completionDirs = append(completionDirs, dir)
+		}
+	}
+	if len(completionDirs) > 0 {
+		usable := ""
+		for _, dir := range completionDirs {
+			if general.FileExist(dir) && checkWritable(dir) == nil {
+				usable = dir
+				break
+			}
+		}
+		if usable != "" {
+			results = append(results, diagnosis{Level: diagnosisOk, Item: "completion_dir", Detail: general.SecondaryText(usable)})
+		} else {
+			results = append(results, diagnosis{
+				Level:  diagnosisWarn,
+				Item:   "completion_dir",
+				Detail: color.Sprintf("none of %s exists or is writable, completion scripts will not be installed", strings.Join(completionDirs, ", ")),
+				Remedy: color.Sprintf("Create one of them, e.g. 'mkdir -p %s'", completionDirs[0]),
+			})
+		}
+	}
+
+	return results
+}
+
+// diagnoseProxies 检查配置的代理是否可以连接
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//
+// 返回：
+//   - 诊断结果
+func diagnoseProxies(config *general.Config) []diagnosis {
+	results := make([]diagnosis, 0)
+
+	proxies := []struct {
+		name  string // 配置项
+		proxy string // 代理地址
+	}{
+		{"http_proxy", config.Variable.HTTPProxy},
+		{"https_proxy", config.Variable.HTTPSProxy},
+	}
+	for _, item := range proxies {
+		if item.proxy == "" {
+			results = append(results, diagnosis{Level: diagnosisOk, Item: item.name, Detail: general.SecondaryText("not set")})
+			continue
+		}
+		if err := checkProxy(item.proxy); err != nil {
+			results = append(results, diagnosis{
+				Level:  diagnosisError,
+				Item:   item.name,
+				Detail: color.Sprintf("%s is unreachable: %s", item.proxy, err),
+				Remedy: color.Sprintf("Start the proxy or clear '%s' in the [variable] table", item.name),
+			})
+			continue
+		}
+		results = append(results, diagnosis{Level: diagnosisOk, Item: item.name, Detail: general.SecondaryText(item.proxy)})
+	}
+
+	return results
+}
+
+// isSourceCache 检查源码临时文件夹中的条目是否为可用的源码缓存
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - name: 条目名
+//   - path: 条目路径
+//
+// 返回：
+//   - 是否为管理程序本身或基于 golang 的程序的可用源码缓存
+func isSourceCache(config *general.Config, name, path string) bool {
+	if name != config.Program.Self.Name && !slices.Contains(config.Program.Go.Names, name) {
+		return false
+	}
+	return general.IsGitRepo(path)
+}
+
+// isResumableDownload 判断 release 临时文件夹中的条目是否为可以续传的未完成下载
+//
+//   - 下载文件存储在以程序名命名的文件夹中，其中只有可以续传的下载文件时不算残留
+//
+// 参数：
+//   - entry: 临时文件夹中的条目
+//   - path: 条目路径
+//
+// 返回：
+//   - 是否为可以续传的未完成下载
+func isResumableDownload(entry os.DirEntry, path string) bool {
+	if !entry.IsDir() {
+		return general.IsResumableDownload(entry)
+	}
+	files, err := os.ReadDir(path)
+	if err != nil || len(files) == 0 {
+		return false
+	}
+	for _, file := range files {
+		if !general.IsResumableDownload(file) {
+			return false
+		}
+	}
+	return true
+}
+
+// diagnoseLeftovers 检查临时文件夹残留和未完成的安装事务
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//
+// 返回：
+//   - 诊断结果
+func diagnoseLeftovers(config *general.Config) []diagnosis {
+	results := make([]diagnosis, 0)
+
+	// 临时文件夹残留，source 安装方式的源码缓存和可以续传的未完成下载不算残留
+	for _, tempDir := range []string{config.Program.ReleaseTemp, config.Program.SourceTemp} {
+		if tempDir == "" || !general.FileExist(tempDir) {
+			continue
+		}
+		entries, err := os.ReadDir(tempDir)
+		if err != nil {
+			continue
+		}
+		cachedNum, resumableNum := 0, 0
+		stale := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			entryPath := filepath.Join(tempDir, entry.Name())
+			if tempDir == config.Program.SourceTemp && isSourceCache(config, entry.Name(), entryPath) {
+				cachedNum++
+				continue
+			}
+			if tempDir == config.Program.ReleaseTemp && isResumableDownload(entry, entryPath) {
+				resumableNum++
+				continue
+			}
+			stale = append(stale, entryPath)
+		}
+		if len(stale) == 0 {
+			detail := general.SecondaryText("empty")
+			switch {
+			case cachedNum > 0:
+				detail = general.SecondaryText(color.Sprintf("%d cached source repositories", cachedNum))
+			case resumableNum > 0:
+				detail = general.SecondaryText(color.Sprintf("%d unfinished downloads kept for resuming", resumableNum))
+			}
+			results = append(results, diagnosis{Level: diagnosisOk, Item: tempDir, Detail: detail})
+			continue
+		}
+		results = append(results, diagnosis{
+			Level:  diagnosisWarn,
+			Item:   tempDir,
+			Detail: color.Sprintf("%d stale entries left by previous runs", len(stale)),
+			Remedy: color.Sprintf("Remove them with 'rm -rf %s'", strings.Join(stale, " ")),
+		})
+	}
+
+	// 未完成的安装事务
+	pending, err := general.PendingTransactions(config.Program.PocketPath)
+	if err != nil {
+		results = append(results, diagnosis{Level: diagnosisWarn, Item: "transactions", Detail: err.Error()})
+	} else if len(pending) > 0 {
+		results = append(results, diagnosis{
+			Level:  diagnosisError,
+			Item:   "transactions",
+			Detail: color.Sprintf("unfinished installation of %s", strings.Join(pending, ", ")),
+			Remedy: "Run 'manager install' to roll them back",
+		})
+	} else {
+		results = append(results, diagnosis{Level: diagnosisOk, Item: "transactions", Detail: general.SecondaryText("no unfinished installation")})
+	}
+
+	return results
+}
+
+// diagnoseLedgers 交叉检查记账文件和磁盘上的程序
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//
+// 返回：
+//   - 诊断结果
+func diagnoseLedgers(config *general.Config) []diagnosis {
+	results := make([]diagnosis, 0)
+
+	// 已配置的程序
+	configured := []string{config.Program.Self.Name}
+	configured = append(configured, config.Program.Go.Names...)
+	configured = append(configured, config.Program.Shell.Names...)
+
+	// 记账文件中的程序：不在配置中的是孤儿，记录了不存在的文件的是悬空记录
+	pocketPrograms := listPocketPrograms(config)
+	for _, program := range pocketPrograms {
+		pocketFile := filepath.Join(config.Program.PocketPath, program, config.Program.PocketFile) // 记账文件路径
+		ledger, err := general.ReadLedger(pocketFile)
+		if err != nil {
+			results = append(results, diagnosis{
+				Level:  diagnosisError,
+				Item:   program,
+				Detail: color.Sprintf("pocket file can not be read: %s", err),
+				Remedy: color.Sprintf("Reinstall it with 'manager install %s'", program),
+			})
+			continue
+		}
+		dangling := make([]string, 0)
+		for _, path := range ledger.Paths() {
+			if !general.FileExist(path) {
+				dangling = append(dangling, path)
+			}
+		}
+		switch {
+		case !slices.Contains(configured, program):
+			results = append(results, diagnosis{
+				Level:  diagnosisWarn,
+				Item:   program,
+				Detail: "has a pocket file but is not in the configuration",
+				Remedy: color.Sprintf("Add it back to the configuration or remove it with 'rm -rf %s'", filepath.Dir(pocketFile)),
+			})
+		case len(dangling) > 0:
+			results = append(results, diagnosis{
+				Level:  diagnosisWarn,
+				Item:   program,
+				Detail: color.Sprintf("pocket file lists missing files: %s", strings.Join(dangling, ", ")),
+				Remedy: color.Sprintf("Reinstall it with 'manager verify --fix %s'", program),
+			})
+		}
+	}
+
+	// 磁盘上存在但没有记账文件的已配置程序
+	for _, program := range configured {
+		if program == "" || slices.Contains(pocketPrograms, program) {
+			continue
+		}
+		if general.FileExist(filepath.Join(config.Program.ProgramPath, program)) {
+			results = append(results, diagnosis{
+				Level:  diagnosisWarn,
+				Item:   program,
+				Detail: "is installed but has no pocket file, it can not be uninstalled or verified",
+				Remedy: color.Sprintf("Reinstall it with 'manager install %s'", program),
+			})
+		}
+	}
+
+	if len(results) == 0 {
+		results = append(results, diagnosis{Level: diagnosisOk, Item: "pocket files", Detail: general.SecondaryText(color.Sprintf("%d consistent", len(pocketPrograms)))})
+	}
+
+	return results
+}
+
+// checkWritable 检查文件夹是否可写，文件夹不存在时检查最近的已存在的上级文件夹
+//
+// 参数：
+//   - dir: 文件夹路径
+//
+// 返回：
+//   - 错误信息
+func checkWritable(dir string) error {
+	for !general.FileExist(dir) {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return fmt.Errorf("No existing parent directory")
+		}
+		dir = parent
+	}
+	file, err := os.CreateTemp(dir, ".manager-doctor-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// checkProxy 检查代理地址是否可以建立 TCP 连接
+//
+// 参数：
+//   - proxy: 代理地址
+//
+// 返回：
+//   - 错误信息
+func checkProxy(proxy string) error {
+	proxyUrl, err := url.Parse(proxy)
+	if err != nil {
+		return err
+	}
+	if proxyUrl.Host == "" { // 没有协议头的代理地址
+		proxyUrl, err = url.Parse("http://" + proxy)
+		if err != nil {
+			return err
+		}
+	}
+	host := proxyUrl.Host
+	if proxyUrl.Port() == "" {
+		switch proxyUrl.Scheme {
+		case "https":
+			host = net.JoinHostPort(proxyUrl.Hostname(), "443")
+		case "socks5", "socks5h":
+			host = net.JoinHostPort(proxyUrl.Hostname(), "1080")
+		default:
+			host = net.JoinHostPort(proxyUrl.Hostname(), "80")
+		}
+	}
+	conn, err := net.DialTimeout("tcp", host, 3*time.Second)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
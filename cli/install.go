@@ -10,8 +10,10 @@ Description: 子命令 'install' 的实现
 package cli
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -19,14 +21,680 @@ import (
 	"github.com/yhyj/manager/general"
 )
 
+// SplitProgramNames 将通过命令行参数指定的程序名按类别拆分，并拒绝未配置的程序名
+//
+//   - 基于 golang 的程序可以使用 'name@version' 的形式固定版本
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - names: 通过命令行参数指定的程序名
+//   - goFlag: 是否在基于 golang 的程序中查找
+//   - shellFlag: 是否在基于 shell 的程序中查找
+//
+// 返回：
+//   - 基于 golang 的程序名
+//   - 基于 shell 的程序名
+//   - 通过参数固定的程序版本，key 为程序名，value 为 Tag
+//   - 错误信息
+func SplitProgramNames(config *general.Config, names []string, goFlag, shellFlag bool) ([]string, []string, map[string]string, error) {
+	goNames := make([]string, 0)      // 基于 golang 的程序名
+	shellNames := make([]string, 0)   // 基于 shell 的程序名
+	pins := make(map[string]string)   // 固定的程序版本
+	unknownNames := make([]string, 0) // 未配置的程序名
+
+	for _, arg := range names {
+		name, version, pinned := strings.Cut(arg, "@")
+		if pinned && version == "" {
+			return nil, nil, nil, fmt.Errorf("Missing version in '%s', it should be: <name>@<version>", arg)
+		}
+		switch {
+		case goFlag && slices.Contains(config.Program.Go.Names, name):
+			if !slices.Contains(goNames, name) {
+				goNames = append(goNames, name)
+			}
+			if pinned {
+				pins[name] = version
+			}
+		case shellFlag && slices.Contains(config.Program.Shell.Names, name):
+			if pinned {
+				return nil, nil, nil, fmt.Errorf("Version pinning is not supported for shell scripts: %s", arg)
+			}
+			if !slices.Contains(shellNames, name) {
+				shellNames = append(shellNames, name)
+			}
+		default:
+			unknownNames = append(unknownNames, arg)
+		}
+	}
+
+	if len(unknownNames) > 0 {
+		return nil, nil, nil, fmt.Errorf("Unknown program name: %s (not in the configured list)", strings.Join(unknownNames, ", "))
+	}
+
+	return goNames, shellNames, pins, nil
+}
+
+// getGolangRelease 按顺序从各镜像获取基于 golang 的程序最新（或固定版本的） Release
+//
+//   - 遵循 prerelease 渠道的程序从 Release 列表中按语义化版本选出最高的版本（包括先行版本）
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - program: 程序名
+//   - try: 按顺序尝试各镜像的方法，tryForges 或 general.TryForges
+//
+// 返回：
+//   - Release 信息
+//   - 获取 Release 信息的代码托管平台
+//   - 错误信息
+func getGolangRelease(config *general.Config, program string, try forgeTrier) (general.Release, general.Forge, error) {
+	var release general.Release
+	forge, err := try(config.Program.Mirrors, func(forge general.Forge) error {
+		var err error
+		if pin := config.Program.Go.Pins[program]; pin != "" {
+			release, err = forge.ReleaseByTag(program, pin)
+			return err
+		}
+		if config.Program.Go.ChannelOf(program) == general.ChannelPrerelease {
+			releases, err := forge.ListReleases(program)
+			if err != nil {
+				return err
+			}
+			release, err = general.HighestRelease(releases, true)
+			return err
+		}
+		release, err = forge.LatestRelease(program)
+		return err
+	})
+	return release, forge, err
+}
+
+// getGolangSourceTag 按顺序从各镜像获取基于 golang 的程序要安装的 Tag，程序固定了版本时使用该版本
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - program: 程序名
+//   - try: 按顺序尝试各镜像的方法，tryForges 或 general.TryForges
+//
+// 返回：
+//   - 要安装的 Tag
+//   - 错误信息
+func getGolangSourceTag(config *general.Config, program string, try forgeTrier) (string, error) {
+	tag := ""
+	_, err := try(config.Program.Mirrors, func(forge general.Forge) error {
+		tags, err := forge.ListTags(program)
+		if err != nil {
+			return err
+		}
+		if pin := config.Program.Go.Pins[program]; pin != "" {
+			tag, err = general.FindTag(tags, pin)
+			return err
+		}
+		includePrerelease := config.Program.Go.ChannelOf(program) == general.ChannelPrerelease
+		if tag = general.HighestTag(tags, includePrerelease); tag == "" {
+			return fmt.Errorf("No tag found in %s channel", config.Program.Go.ChannelOf(program))
+		}
+		return nil
+	})
+	return tag, err
+}
+
+// golangNeedsUpdate 判断基于 golang 的程序是否需要安装/更新
+//
+//   - 固定了版本时只要版本不同就安装（允许降级），否则只有远端版本比本地版本新时才更新
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - program: 程序名
+//   - remoteTag: 远端版本
+//   - localVersion: 本地版本
+//   - installed: 本地程序是否存在
+//
+// 返回：
+//   - 是否需要安装/更新
+func golangNeedsUpdate(config *general.Config, program, remoteTag, localVersion string, installed bool) bool {
+	if !installed {
+		return true
+	}
+	if config.Program.Go.Pins[program] != "" {
+		return remoteTag != localVersion
+	}
+	return general.IsNewerVersion(remoteTag, localVersion)
+}
+
+// channelNote 获取输出中说明程序发布渠道的文本
+//
+// 参数：
+//   - channel: 发布渠道，为空时（例如 shell 脚本）不说明
+//
+// 返回：
+//   - 说明文本
+func channelNote(channel string) string {
+	if channel == "" {
+		return ""
+	}
+	return " " + general.SecondaryText("[", channel, " channel]")
+}
+
+// upToDateMessage 获取无需更新时输出的信息，本地版本比远端版本新时说明远端版本
+//
+// 参数：
+//   - remoteTag: 远端版本
+//   - localVersion: 本地版本
+//
+// 返回：
+//   - 无需更新信息
+func upToDateMessage(remoteTag, localVersion string) string {
+	if remoteTag == localVersion {
+		return general.LatestVersionMessage
+	}
+	return color.Sprintf(general.NewerThanRemoteMessage, remoteTag)
+}
+
+// getShellSourceHash 按顺序从各镜像获取基于 shell 的脚本的远端最新 Hash
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - program: 脚本名
+//   - try: 按顺序尝试各镜像的方法，tryForges 或 general.TryForges
+//
+// 返回：
+//   - 远端最新 Hash
+//   - 获取 Hash 的代码托管平台
+//   - 错误信息
+func getShellSourceHash(config *general.Config, program string, try forgeTrier) (string, general.Forge, error) {
+	hash := ""
+	forge, err := try(config.Program.Mirrors, func(forge general.Forge) error {
+		var err error
+		hash, err = forge.FileHash(config.Program.Shell.Repo, color.Sprintf("%s/%s", config.Program.Shell.Dir, program))
+		return err
+	})
+	return hash, forge, err
+}
+
+// forgeTrier 按顺序对各镜像的代码托管平台执行操作的方法
+type forgeTrier func(mirrors []general.MirrorConfig, action func(forge general.Forge) error) (general.Forge, error)
+
+// tryForges 按顺序对各镜像的代码托管平台执行操作，镜像失败且还有其他镜像时输出警告，回退到其他镜像成功时输出使用的镜像
+//
+// 参数：
+//   - mirrors: 镜像列表
+//   - action: 对单个代码托管平台执行的操作
+//
+// 返回：
+//   - 成功的代码托管平台
+//   - 错误信息，所有镜像都失败时包含每个镜像的错误
+func tryForges(mirrors []general.MirrorConfig, action func(forge general.Forge) error) (general.Forge, error) {
+	attempts := 0
+	forge, err := general.TryForges(mirrors, func(forge general.Forge) error {
+		attempts++
+		err := action(forge)
+		if err != nil && attempts < len(mirrors) {
+			color.Printf("%s %s %s %s\n", general.WarningFlag, general.FgYellowText(forge.Mirror().Label()), general.SecondaryText("failed, try next mirror:"), err)
+		}
+		return err
+	})
+	if err == nil && attempts > 1 {
+		color.Printf("%s %s %s\n", general.WarningFlag, general.SecondaryText("Fell back to mirror"), general.FgYellowText(forge.Mirror().Label()))
+	}
+	return forge, err
+}
+
+// downloadReleaseFile 按顺序从各镜像下载 Release 文件，优先使用获取 Release 信息的镜像
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - forge: 获取 Release 信息的代码托管平台
+//   - program: 程序名
+//   - tag: Release 的 Tag
+//   - fileInfo: Release 文件信息，其下载地址用于获取 Release 信息的镜像
+//   - localPath: 下载文件保存路径
+//
+// 返回：
+//   - 实际使用的下载地址
+//   - 错误信息
+func downloadReleaseFile(config *general.Config, forge general.Forge, program, tag string, fileInfo general.ReleaseAsset, localPath string) (string, error) {
+	preferred := forge.Mirror()
+	fileUrl := ""
+	_, err := tryForges(general.PreferMirror(config.Program.Mirrors, preferred), func(forge general.Forge) error {
+		if forge.Mirror() == preferred && fileInfo.DownloadUrl != "" {
+			fileUrl = preferred.Rewrite(fileInfo.DownloadUrl)
+		} else {
+			fileUrl = forge.ReleaseDownloadUrl(program, tag, fileInfo.Name)
+		}
+		general.ProgressParameters["suffix"] = color.Sprintf("from %s release:", forge.Mirror().Label())
+		return general.DownloadFile(fileUrl, localPath, general.ProgressParameters)
+	})
+	return fileUrl, err
+}
+
+// verifyChecksumsSignature 下载 Checksums 文件的签名，并使用配置中固定的公钥校验
+//
+//   - 未固定公钥且不要求校验时跳过
+//   - Release 中没有签名文件时，要求校验则失败，否则输出警告后跳过
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - forge: 获取 Release 信息的代码托管平台
+//   - release: Release 信息
+//   - program: 程序名
+//   - checksumsLocalPath: Checksums 文件本地存储位置
+//
+// 返回：
+//   - 错误信息，签名校验未通过时不为 nil
+func verifyChecksumsSignature(config *general.Config, forge general.Forge, release general.Release, program, checksumsLocalPath string) error {
+	signatureConfig := config.Program.Signature
+	if !signatureConfig.Enabled() {
+		return nil
+	}
+
+	// 查找签名文件
+	checksumsName := filepath.Base(checksumsLocalPath)
+	signatureName := signatureConfig.FileName(checksumsName)
+	var signatureInfo general.ReleaseAsset
+	for _, asset := range release.Assets {
+		if asset.Name == signatureName {
+			signatureInfo = asset
+		}
+	}
+	if signatureInfo.Name == "" {
+		if signatureConfig.Required {
+			return fmt.Errorf("Signature file %s not found in release %s", signatureName, release.Tag)
+		}
+		color.Printf("%s %s %s\n", general.WarningFlag, general.FgYellowText(signatureName), general.SecondaryText("not found in release, skip signature verification"))
+		return nil
+	}
+
+	// 下载签名文件
+	general.ProgressParameters["fileName"] = color.Sprintf("[%s]", signatureName)
+	signatureLocalPath := filepath.Join(filepath.Dir(checksumsLocalPath), signatureName) // 签名文件本地存储位置
+	if _, err := downloadReleaseFile(config, forge, program, release.Tag, signatureInfo, signatureLocalPath); err != nil {
+		return err
+	}
+
+	// 校验签名
+	checksums, err := os.ReadFile(checksumsLocalPath)
+	if err != nil {
+		return err
+	}
+	signature, err := os.ReadFile(signatureLocalPath)
+	if err != nil {
+		return err
+	}
+	if err := general.VerifySignature(signatureConfig, checksums, signature); err != nil {
+		return err
+	}
+	color.Printf("%s %s %s\n", general.SuccessFlag, general.FgGreenText(checksumsName), general.SecondaryText(signatureConfig.Type, " signature verified"))
+
+	return nil
+}
+
+// cloneFromMirrors 按顺序从各镜像同步基于 golang 的程序的源码缓存到指定 Tag，并输出每个镜像的同步结果
+//
+//   - 源码缓存不存在时克隆，存在时只拉取该 Tag 并检出
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - program: 程序名
+//   - tag: 要构建的 Tag，即作为远端版本的 Tag
+//
+// 返回：
+//   - 实际使用的克隆地址
+//   - 错误信息
+func cloneFromMirrors(config *general.Config, program, tag string) (string, error) {
+	cloneUrl := ""
+	_, err := general.TryForges(config.Program.Mirrors, func(forge general.Forge) error {
+		cloneUrl = forge.CloneUrl(program)
+		action := "Clone"
+		if general.FileExist(filepath.Join(config.Program.SourceTemp, program)) {
+			action = "Fetch"
+		}
+		color.Printf("%s %s %s %s %s ", general.DownloadFlag, general.LightText(action), general.FgGreenText(program), general.FgYellowText(tag), color.Sprintf("from %s", forge.Mirror().Label()))
+		if err := general.SyncRepoViaHTTP(config.Program.SourceTemp, cloneUrl, program, tag); err != nil {
+			color.Printf("%s\n", general.DangerText("error -> ", err))
+			return err
+		}
+		color.Println(general.SuccessText("success"))
+		return nil
+	})
+	return cloneUrl, err
+}
+
+// selectPrograms 确定要操作的程序：优先使用命令行参数指定的程序，其次是所有已安装程序，最后由用户在选择器中选择
+//
+// 参数：
+//   - choices: 可选项
+//   - installed: 已安装的程序
+//   - notes: 选择器中程序的附加说明，可以为 nil
+//   - programs: 通过命令行参数指定的程序名
+//   - allInstalled: 是否选择所有已安装的程序
+//   - negatives: 希望选择器在运行后输出的信息
+//
+// 返回：
+//   - 已选程序
+//   - 错误信息
+func selectPrograms(choices, installed []string, notes map[string]string, programs []string, allInstalled bool, negatives string) ([]string, error) {
+	if allInstalled {
+		selected := slices.Clone(installed)
+		for _, program := range programs {
+			if !slices.Contains(selected, program) {
+				selected = append(selected, program)
+			}
+		}
+		return selected, nil
+	}
+	if len(programs) > 0 {
+		return slices.Clone(programs), nil
+	}
+	return general.MultipleSelectionFilter(choices, installed, notes, negatives)
+}
+
+// RecoverInstallations 撤销上次运行时因崩溃或中断而未完成的安装
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+func RecoverInstallations(config *general.Config) {
+	recovered, err := general.RecoverTransactions(config.Program.PocketPath)
+	for _, program := range recovered {
+		color.Warn.Tips("Unfinished installation of \x1b[3m%s\x1b[0m has been rolled back", general.FgCyanText(program))
+	}
+	if err != nil {
+		fileName, lineNo := general.GetCallerInfo()
+		color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+	}
+}
+
+// installProgramFile 在事务中安装程序主文件并记账
+//
+// 参数：
+//   - transaction: 安装事务
+//   - sourceFile: 待安装的程序文件
+//   - localProgram: 本地程序路径
+//   - ledger: 记账信息
+//
+// 返回：
+//   - 错误信息
+func installProgramFile(transaction *general.Transaction, sourceFile, localProgram string, ledger *general.Ledger) error {
+	if err := transaction.Install(sourceFile, localProgram, 0755); err != nil {
+		return err
+	}
+	return ledger.AddFile(localProgram)
+}
+
+// installReleaseFiles 在事务中安装 release 压缩包解压得到的程序和资源文件并记账
+//
+// 参数：
+//   - transaction: 安装事务
+//   - config: 解析 toml 配置文件得到的配置项
+//   - program: 程序名
+//   - archivedProgram: 解压得到的程序
+//   - archivedResourcesFolder: 解压得到的资源文件夹
+//   - localProgram: 本地程序路径
+//   - ledger: 记账信息
+//
+// 返回：
+//   - 错误信息
+func installReleaseFiles(transaction *general.Transaction, config *general.Config, program, archivedProgram, archivedResourcesFolder, localProgram string, ledger *general.Ledger) error {
+	// 安装程序
+	if err := installProgramFile(transaction, archivedProgram, localProgram, ledger); err != nil {
+		return err
+	}
+
+	// 安装资源文件 - desktop 文件
+	archivedResourcesDesktopFile := filepath.Join(archivedResourcesFolder, "applications", color.Sprintf("%s.desktop", program))   // 解压得到的资源文件 - desktop 文件
+	localResourcesDesktopFile := filepath.Join(config.Program.ResourcesPath, "applications", color.Sprintf("%s.desktop", program)) // 本地资源文件 - desktop 文件
+	if general.FileExist(archivedResourcesDesktopFile) {
+		if err := transaction.Install(archivedResourcesDesktopFile, localResourcesDesktopFile, 0644); err != nil {
+			return err
+		}
+		if err := ledger.AddFile(localResourcesDesktopFile); err != nil {
+			return err
+		}
+	}
+
+	// 安装资源文件 - icon 文件
+	archivedResourcesIconFolder := filepath.Join(archivedResourcesFolder, "pixmaps")   // 解压得到的资源文件 - icon 文件夹
+	localResourcesIconFolder := filepath.Join(config.Program.ResourcesPath, "pixmaps") // 本地资源文件 - icon 文件夹
+	if general.FileExist(archivedResourcesIconFolder) {
+		files, err := general.ListFolderFiles(archivedResourcesIconFolder)
+		if err != nil {
+			return err
+		}
+		if err := general.CreateDir(localResourcesIconFolder); err != nil {
+			return err
+		}
+		for _, file := range files {
+			archivedResourcesIconFile := filepath.Join(archivedResourcesIconFolder, file) // 解压得到的资源文件 - icon 文件
+			localResourcesIconFile := filepath.Join(localResourcesIconFolder, file)       // 本地资源文件 - icon 文件
+			if err := transaction.Install(archivedResourcesIconFile, localResourcesIconFile, 0644); err != nil {
+				return err
+			}
+			if err := ledger.AddFile(localResourcesIconFile); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// installCompletionScript 在事务中安装程序生成的 zsh 自动补全脚本
+//
+//   - 先将脚本生成到临时文件，生成成功后才覆盖已有的自动补全脚本
+//
+// 参数：
+//   - transaction: 安装事务
+//   - localProgram: 本地程序路径
+//   - completionFile: 自动补全脚本路径
+//
+// 返回：
+//   - 错误信息
+func installCompletionScript(transaction *general.Transaction, localProgram, completionFile string) error {
+	script, _, err := general.RunCommandToBuffer(localProgram, []string{"completion", "zsh"})
+	if err != nil {
+		return err
+	}
+	if script == "" {
+		return fmt.Errorf("%s generated an empty completion script", localProgram)
+	}
+
+	tempFile, err := os.CreateTemp("", filepath.Base(completionFile))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+	if _, err := tempFile.WriteString(script + "\n"); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	return transaction.Install(tempFile.Name(), completionFile, 0644)
+}
+
+// installCompiledProgram 在事务中安装编译得到的程序，使用 Makefile 构建时使用 `make install` 命令安装
+//
+//   - `make install` 通过 PREFIX、BINDIR 参数安装到临时 DESTDIR 中，再将得到的所有文件在事务中安装到实际路径并记账
+//
+// 参数：
+//   - transaction: 安装事务
+//   - config: 解析 toml 配置文件得到的配置项
+//   - buildPlan: 构建方案
+//   - compileProgram: 编译得到的程序
+//   - localProgram: 本地程序路径
+//   - ledger: 记账信息
+//
+// 返回：
+//   - 错误信息
+func installCompiledProgram(transaction *general.Transaction, config *general.Config, buildPlan general.BuildPlan, compileProgram, localProgram string, ledger *general.Ledger) error {
+	if buildPlan.UseMake {
+		destDir, err := os.MkdirTemp("", "manager-destdir-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(destDir)
+		if _, stderr, err := general.RunCommandToBufferWithEnv("make", config.Program.MakeInstallArgs(destDir), buildPlan.Env); err != nil {
+			return fmt.Errorf("%s %s", err, stderr)
+		}
+		return installDestDirFiles(transaction, destDir, ledger)
+	}
+	return installProgramFile(transaction, compileProgram, localProgram, ledger)
+}
+
+// installDestDirFiles 在事务中将 `make install` 安装到临时 DESTDIR 中的文件安装到实际路径并记账
+//
+//   - 文件在 DESTDIR 中的相对路径即其实际的绝对路径
+//   - 支持普通文件和符号链接，空文件夹不会被安装
+//
+// 参数：
+//   - transaction: 安装事务
+//   - destDir: 临时 DESTDIR
+//   - ledger: 记账信息
+//
+// 返回：
+//   - 错误信息
+func installDestDirFiles(transaction *general.Transaction, destDir string, ledger *general.Ledger) error {
+	installedNum := 0 // 已安装的文件数
+	err := filepath.WalkDir(destDir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(destDir, path)
+		if err != nil {
+			return err
+		}
+		targetFile := filepath.Join(string(filepath.Separator), relPath) // 实际路径
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		if err := transaction.MkdirAll(filepath.Dir(targetFile)); err != nil {
+			return err
+		}
+		switch {
+		case info.Mode().IsRegular():
+			if err := transaction.Install(path, targetFile, info.Mode().Perm()); err != nil {
+				return err
+			}
+		case info.Mode()&os.ModeSymlink != 0:
+			linkTarget, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := transaction.Track(targetFile); err != nil {
+				return err
+			}
+			if _, err := os.Lstat(targetFile); err == nil {
+				if err := general.DeleteFile(targetFile); err != nil {
+					return err
+				}
+			}
+			if err := os.Symlink(linkTarget, targetFile); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("Unsupported file type installed by make install: %s", targetFile)
+		}
+		installedNum++
+		return ledger.AddFile(targetFile)
+	})
+	if err != nil {
+		return err
+	}
+	if installedNum == 0 {
+		return fmt.Errorf("make install did not install any file")
+	}
+	return nil
+}
+
+// CheckProgramUpdates 检查指定类别的程序是否需要安装/更新，只查询远端版本，不下载任何文件
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - category: 要检查的类别，支持 self、go 和 shell
+//   - programs: 通过命令行参数指定的程序名，为空时检查该类别的所有程序
+//
+// 返回：
+//   - 需要安装/更新的程序数
+//   - 无法获取远端版本（网络错误、限流等）的程序数
+func CheckProgramUpdates(config *general.Config, category string, programs []string) (int, int) {
+	// 设置代理
+	general.SetVariable("http_proxy", config.Variable.HTTPProxy)
+	general.SetVariable("https_proxy", config.Variable.HTTPSProxy)
+	// 设置 API 认证和限流策略
+	general.SetupApiAuth(config)
+
+	// 从配置读取指定类别的程序名
+	var (
+		programNames []string // 程序名切片
+		inspectAs    string   // 获取程序状态时使用的类别
+	)
+	switch category {
+	case "self":
+		programNames, inspectAs = []string{config.Program.Self.Name}, "go"
+	case "go":
+		programNames, inspectAs = slices.Clone(config.Program.Go.Names), "go"
+	case "shell":
+		programNames, inspectAs = slices.Clone(config.Program.Shell.Names), "shell"
+	default:
+		fileName, lineNo := general.GetCallerInfo()
+		color.Printf("%s %s Category '%s' mismatch\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), category)
+		return 0, 1
+	}
+	if len(programs) > 0 {
+		programNames = slices.Clone(programs)
+	}
+	sort.Strings(programNames)
+
+	// 开始检查提示
+	color.Info.Tips("Check \x1b[3m%s\x1b[0m programs", general.FgCyanText(category, "-based"))
+	color.Printf("%s\n", strings.Repeat(general.Separator1st, general.SeparatorBaseLength))
+
+	pendingNum := 0 // 需要安装/更新的程序数
+	failedNum := 0  // 无法获取远端版本的程序数
+	for _, program := range programNames {
+		status := inspectProgram(config, inspectAs, program)
+
+		var text string
+		switch {
+		case status.RemoteErr != nil: // 无法获取远端版本
+			failedNum++
+			fileName, lineNo := general.GetCallerInfo()
+			text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), status.RemoteErr)
+		case !status.Installed: // 未安装
+			pendingNum++
+			text = color.Sprintf("%s %s %s %s%s\n", general.DownloadFlag, general.FgGreenText(program), general.FgYellowText(status.RemoteVersion), general.FgMagentaText("would be installed"), channelNote(status.Channel))
+		case status.Outdated: // 需要更新
+			pendingNum++
+			text = color.Sprintf("%s %s %s %s %s %s%s\n", general.DownloadFlag, general.FgGreenText(program), general.FgYellowText(status.LocalVersion), general.Indicator, general.NoteText(status.RemoteVersion), general.FgMagentaText("would be updated"), channelNote(status.Channel))
+		default: // 已是最新
+			text = color.Sprintf("%s %s %s %s%s\n", general.LatestFlag, general.FgGreenText(program), general.FgYellowText(status.LocalVersion), upToDateMessage(status.RemoteVersion, status.LocalVersion), channelNote(status.Channel))
+		}
+		color.Print(text)
+
+		// 分隔符和延时（延时使输出更加顺畅）
+		general.PrintDelimiter(general.RealLength(text)) // 分隔符
+		general.Delay(general.DelayTime)                 // 添加一个延时，使输出更加顺畅
+	}
+
+	return pendingNum, failedNum
+}
+
 // InstallSelfProgram 安装/更新管理程序本身
 //
 // 参数：
 //   - config: 解析 toml 配置文件得到的配置项
-func InstallSelfProgram(config *general.Config) {
+//   - force: 版本一致时是否仍然重新安装
+//   - clean: 是否删除源码缓存后重新克隆（用于 source 安装方式）
+func InstallSelfProgram(config *general.Config, force, clean bool) {
 	// 设置代理
 	general.SetVariable("http_proxy", config.Variable.HTTPProxy)
 	general.SetVariable("https_proxy", config.Variable.HTTPSProxy)
+	// 设置 API 认证和限流策略
+	general.SetupApiAuth(config)
 
 	// 设置进度条参数
 	general.ProgressParameters["view"] = "1"
@@ -47,7 +715,6 @@ func InstallSelfProgram(config *general.Config) {
 
 	// 记账文件
 	pocketFile := filepath.Join(config.Program.PocketPath, name, config.Program.PocketFile) // 记账文件路径
-	var writeMode = "a"                                                                     // 写入模式
 
 	// 使用配置的安装方式进行安装
 	switch strings.ToLower(config.Program.Method) {
@@ -59,11 +726,8 @@ func InstallSelfProgram(config *general.Config) {
 			return
 		}
 
-		// API
-		goGithubLatestReleaseTagApi := color.Sprintf(general.GoLatestReleaseTagApiFormat, config.Program.Go.ReleaseApi, config.Program.Go.GithubUsername, name) // 请求远端仓库最新 Tag
-
-		// 请求 API - GitHub
-		body, err := general.RequestApi(goGithubLatestReleaseTagApi)
+		// 按顺序从各镜像获取 Release 信息
+		release, forge, err := getGolangRelease(config, name, tryForges)
 		if err != nil {
 			fileName, lineNo := general.GetCallerInfo()
 			text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
@@ -75,31 +739,21 @@ func InstallSelfProgram(config *general.Config) {
 			return
 		}
 		// 获取远端版本（用于 release 安装方法）
-		remoteTag, err := general.GetLatestReleaseTag(body)
-		if err != nil {
-			fileName, lineNo := general.GetCallerInfo()
-			text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-			color.Print(text)
-			// 分隔符和延时（延时使输出更加顺畅）
-			textLength = general.RealLength(text) // 分隔符长度
-			general.PrintDelimiter(textLength)    // 分隔符
-			general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
-			return
-		}
+		remoteTag := release.Tag
 
 		// 获取本地程序版本信息
 		localVersion, _, commandErr := general.RunCommandToBuffer(localProgram, programVersionArgs)
 
 		// 比较远端和本地版本
-		if remoteTag == localVersion { // 版本一致，则输出无需更新信息
-			text := color.Sprintf("%s %s %s %s\n", general.LatestFlag, general.FgGreenText(name), general.FgYellowText(localVersion), general.LatestVersionMessage)
+		if !golangNeedsUpdate(config, name, remoteTag, localVersion, commandErr == nil) && !force { // 远端版本不比本地版本新，则输出无需更新信息
+			text := color.Sprintf("%s %s %s %s\n", general.LatestFlag, general.FgGreenText(name), general.FgYellowText(localVersion), upToDateMessage(remoteTag, localVersion))
 			color.Print(text)
 			textLength = general.RealLength(text) // 分隔符长度
-		} else { // 版本不一致，则安装或更新程序，并输出已安装/更新信息
-			// 下载远端文件（如果 Temp 中已有远端文件则删除重新下载）
+		} else { // 远端版本更新或未安装，则安装或更新程序，并输出已安装/更新信息
+			// 下载远端文件（如果 Temp 中已有远端文件则删除重新下载，保留未完成的下载以便续传）
 			goReleaseTempDir := filepath.Join(config.Program.ReleaseTemp, name)
 			if general.FileExist(goReleaseTempDir) {
-				if err := os.RemoveAll(goReleaseTempDir); err != nil {
+				if err := general.CleanDownloadDir(goReleaseTempDir); err != nil {
 					fileName, lineNo := general.GetCallerInfo()
 					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 					color.Print(text)
@@ -110,21 +764,24 @@ func InstallSelfProgram(config *general.Config) {
 					return
 				}
 			}
-			// 组装需要的文件的名称
-			fileName := general.FileName{}
-			// - checksums.txt
-			fileName.ChecksumsFile = "checksums.txt"
-			// - Archive File
-			fileType := func() string {
-				if general.Platform == "windows" {
-					return "zip"
-				}
-				return "tar.gz"
-			}()
-			archiveFileNameWithoutFileType := color.Sprintf("%s_%s_%s_%s", name, remoteTag, general.Platform, general.Arch)
-			fileName.ArchiveFile = color.Sprintf("%s.%s", archiveFileNameWithoutFileType, fileType)
+			// 根据文件名模板组装需要的文件的名称
+			assetNames, err := config.Program.Go.ReleaseAssets(name, remoteTag)
+			if err != nil {
+				fileName, lineNo := general.GetCallerInfo()
+				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+				color.Print(text)
+				// 分隔符和延时（延时使输出更加顺畅）
+				textLength = general.RealLength(text) // 分隔符长度
+				general.PrintDelimiter(textLength)    // 分隔符
+				general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
+				return
+			}
+			fileName := general.FileName{
+				ChecksumsFile: assetNames.Checksums, // 校验文件
+				ArchiveFiles:  assetNames.Archives,  // 各种格式的压缩包或无需解压的可执行文件
+			}
 			// 获取 Release 文件信息
-			filesInfo, err := general.GetReleaseFileInfo(body, fileName)
+			filesInfo, err := general.GetReleaseFileInfo(release, fileName)
 			if err != nil {
 				fileName, lineNo := general.GetCallerInfo()
 				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
@@ -141,9 +798,19 @@ func InstallSelfProgram(config *general.Config) {
 			general.ProgressParameters["prefix"] = "Download"
 			general.ProgressParameters["project"] = color.Sprintf("[%s]", name)
 			general.ProgressParameters["fileName"] = color.Sprintf("[%s]", filesInfo.ChecksumsFileInfo.Name)
-			general.ProgressParameters["suffix"] = "from github release:"
 			checksumsLocalPath := filepath.Join(config.Program.ReleaseTemp, name, filesInfo.ChecksumsFileInfo.Name) // Checksums 文件本地存储位置
-			if err := general.DownloadFile(filesInfo.ChecksumsFileInfo.DownloadUrl, checksumsLocalPath, general.ProgressParameters); err != nil {
+			if _, err := downloadReleaseFile(config, forge, name, remoteTag, filesInfo.ChecksumsFileInfo, checksumsLocalPath); err != nil {
+				fileName, lineNo := general.GetCallerInfo()
+				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+				color.Print(text)
+				// 分隔符和延时（延时使输出更加顺畅）
+				textLength = general.RealLength(text) // 分隔符长度
+				general.PrintDelimiter(textLength)    // 分隔符
+				general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
+				return
+			}
+			// 校验 Checksums 文件的签名，校验通过前不信任其中的任何 Hash
+			if err := verifyChecksumsSignature(config, forge, release, name, checksumsLocalPath); err != nil {
 				fileName, lineNo := general.GetCallerInfo()
 				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 				color.Print(text)
@@ -158,9 +825,9 @@ func InstallSelfProgram(config *general.Config) {
 			general.ProgressParameters["prefix"] = "Download"
 			general.ProgressParameters["project"] = color.Sprintf("[%s]", name)
 			general.ProgressParameters["fileName"] = color.Sprintf("[%s]", filesInfo.ArchiveFileInfo.Name)
-			general.ProgressParameters["suffix"] = "from github release:"
 			archiveLocalPath := filepath.Join(config.Program.ReleaseTemp, name, filesInfo.ArchiveFileInfo.Name) // Release 文件本地存储位置
-			if err := general.DownloadFile(filesInfo.ArchiveFileInfo.DownloadUrl, archiveLocalPath, general.ProgressParameters); err != nil {
+			archiveUrl, err := downloadReleaseFile(config, forge, name, remoteTag, filesInfo.ArchiveFileInfo, archiveLocalPath)
+			if err != nil {
 				fileName, lineNo := general.GetCallerInfo()
 				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 				color.Print(text)
@@ -195,8 +862,12 @@ func InstallSelfProgram(config *general.Config) {
 				return
 			}
 			if verificationResult { // 压缩包校验通过
-				// 解压压缩包
-				if err := general.UnzipFile(archiveLocalPath, goReleaseTempDir); err != nil {
+				// 解压压缩包，无需解压的可执行文件直接安装
+				archivedFolder := filepath.Join(goReleaseTempDir, general.TrimArchiveType(filesInfo.ArchiveFileInfo.Name)) // 解压得到的文件夹
+				archivedProgram := filepath.Join(archivedFolder, assetNames.BinaryPath(filesInfo.ArchiveFileInfo.Name))    // 解压得到的程序
+				if general.IsExecutableFile(archiveLocalPath) {
+					archivedProgram = archiveLocalPath
+				} else if err := general.UnzipFile(archiveLocalPath, goReleaseTempDir); err != nil {
 					fileName, lineNo := general.GetCallerInfo()
 					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 					color.Print(text)
@@ -206,226 +877,65 @@ func InstallSelfProgram(config *general.Config) {
 					general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
 					return
 				}
-				archivedProgram := filepath.Join(goReleaseTempDir, archiveFileNameWithoutFileType, name)                // 解压得到的程序
-				archivedResourcesFolder := filepath.Join(goReleaseTempDir, archiveFileNameWithoutFileType, "resources") // 解压得到的资源文件夹
+				archivedResourcesFolder := filepath.Join(archivedFolder, "resources") // 解压得到的资源文件夹
 
-				// 初始化记账文件
-				general.InitPocketFile(pocketFile)
-				// 检测本地程序是否存在
-				if commandErr != nil { // 不存在，安装
-					// 安装程序
-					if err := general.Install(archivedProgram, localProgram, 0755); err != nil {
+				// 开始安装事务，失败时撤销本次写入的文件
+				transaction, err := general.BeginTransaction(config.Program.PocketPath, name, pocketFile)
+				if err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+					color.Print(text)
+					// 分隔符和延时（延时使输出更加顺畅）
+					textLength = general.RealLength(text) // 分隔符长度
+					general.PrintDelimiter(textLength)    // 分隔符
+					general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
+					return
+				}
+				// 更新前在事务中备份当前版本，用于回滚，安装失败时一并撤销
+				if commandErr == nil {
+					if err := general.BackupVersion(transaction, pocketFile, localProgram, localVersion, config.Program.RollbackKeep); err != nil {
 						fileName, lineNo := general.GetCallerInfo()
 						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 						color.Print(text)
-						// 分隔符和延时（延时使输出更加顺畅）
-						textLength = general.RealLength(text) // 分隔符长度
-						general.PrintDelimiter(textLength)    // 分隔符
-						general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
-						return
-					} else {
-						// 记账
-						if err := general.WriteFileWithNewLine(pocketFile, localProgram, writeMode); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-						}
-
-						// 为已安装的程序设置可执行权限
-						if err := os.Chmod(localProgram, 0755); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							color.Print(text)
-							// 分隔符和延时（延时使输出更加顺畅）
-							textLength = general.RealLength(text) // 分隔符长度
-							general.PrintDelimiter(textLength)    // 分隔符
-							general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
-							return
-						}
-					}
-
-					// 安装资源文件 - desktop 文件
-					archivedResourcesDesktopFile := filepath.Join(archivedResourcesFolder, "applications", color.Sprintf("%s.desktop", name))   // 解压得到的资源文件 - desktop 文件
-					localResourcesDesktopFile := filepath.Join(config.Program.ResourcesPath, "applications", color.Sprintf("%s.desktop", name)) // 本地资源文件 - desktop 文件
-					if general.FileExist(archivedResourcesDesktopFile) {
-						if err := general.Install(archivedResourcesDesktopFile, localResourcesDesktopFile, 0644); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							color.Print(text)
-							// 分隔符和延时（延时使输出更加顺畅）
-							textLength = general.RealLength(text) // 分隔符长度
-							general.PrintDelimiter(textLength)    // 分隔符
-							general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
-							return
-						}
-						// 记账
-						if err := general.WriteFileWithNewLine(pocketFile, localResourcesDesktopFile, writeMode); err != nil {
+						// 撤销本次安装
+						if err := transaction.Rollback(); err != nil {
 							fileName, lineNo := general.GetCallerInfo()
-							color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-						}
-					}
-
-					// 安装资源文件 - icon 文件
-					archivedResourcesIconFolder := filepath.Join(archivedResourcesFolder, "pixmaps")   // 解压得到的资源文件 - icon 文件夹
-					localResourcesIconFolder := filepath.Join(config.Program.ResourcesPath, "pixmaps") // 本地资源文件 - icon 文件夹
-					if general.FileExist(archivedResourcesIconFolder) {
-						files, err := general.ListFolderFiles(archivedResourcesIconFolder)
-						if err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+							text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 							color.Print(text)
-							// 分隔符和延时（延时使输出更加顺畅）
-							textLength = general.RealLength(text) // 分隔符长度
-							general.PrintDelimiter(textLength)    // 分隔符
-							general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
-							return
-						}
-						if !general.FileExist(localResourcesIconFolder) {
-							if err := general.CreateDir(localResourcesIconFolder); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
-								return
-							}
 						}
-						for _, file := range files {
-							archivedResourcesIconFile := filepath.Join(archivedResourcesIconFolder, file) // 解压得到的资源文件 - icon 文件
-							localResourcesIconFile := filepath.Join(localResourcesIconFolder, file)       // 本地资源文件 - icon 文件
-							if err := general.Install(archivedResourcesIconFile, localResourcesIconFile, 0644); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
-								continue
-							}
-							// 记账
-							if err := general.WriteFileWithNewLine(pocketFile, localResourcesIconFile, writeMode); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							}
-						}
-					}
-					// 本次安装结束分隔符
-					text := color.Sprintf("%s %s %s %s\n", general.SuccessFlag, general.FgGreenText(name), general.FgYellowText(remoteTag), general.FgMagentaText("installed"))
-					color.Print(text)
-					textLength = general.RealLength(text) // 分隔符长度
-				} else { // 存在，更新
-					// 删除已安装的旧程序
-					if err := os.Remove(localProgram); err != nil {
-						fileName, lineNo := general.GetCallerInfo()
-						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-						color.Print(text)
 						// 分隔符和延时（延时使输出更加顺畅）
 						textLength = general.RealLength(text) // 分隔符长度
 						general.PrintDelimiter(textLength)    // 分隔符
 						general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
 						return
 					}
-
-					// 安装程序
-					if err := general.Install(archivedProgram, localProgram, 0755); err != nil {
+				}
+				// 初始化记账信息
+				ledger := general.NewLedger(name, remoteTag, "release", archiveUrl)
+				// 安装程序和资源文件
+				if err := installReleaseFiles(transaction, config, name, archivedProgram, archivedResourcesFolder, localProgram, ledger); err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+					color.Print(text)
+					// 撤销本次安装
+					if err := transaction.Rollback(); err != nil {
 						fileName, lineNo := general.GetCallerInfo()
-						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+						text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 						color.Print(text)
-						// 分隔符和延时（延时使输出更加顺畅）
-						textLength = general.RealLength(text) // 分隔符长度
-						general.PrintDelimiter(textLength)    // 分隔符
-						general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
-						return
-					} else {
-						// 记账
-						if err := general.WriteFileWithNewLine(pocketFile, localProgram, writeMode); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-						}
-
-						// 为已安装的程序设置可执行权限
-						if err := os.Chmod(localProgram, 0755); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							color.Print(text)
-							// 分隔符和延时（延时使输出更加顺畅）
-							textLength = general.RealLength(text) // 分隔符长度
-							general.PrintDelimiter(textLength)    // 分隔符
-							general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
-							return
-						}
-					}
-
-					// 安装资源文件 - desktop 文件
-					archivedResourcesDesktopFile := filepath.Join(archivedResourcesFolder, "applications", color.Sprintf("%s.desktop", name))   // 解压得到的资源文件 - desktop 文件
-					localResourcesDesktopFile := filepath.Join(config.Program.ResourcesPath, "applications", color.Sprintf("%s.desktop", name)) // 本地资源文件 - desktop 文件
-					if general.FileExist(archivedResourcesDesktopFile) {
-						if err := general.Install(archivedResourcesDesktopFile, localResourcesDesktopFile, 0644); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							color.Print(text)
-							// 分隔符和延时（延时使输出更加顺畅）
-							textLength = general.RealLength(text) // 分隔符长度
-							general.PrintDelimiter(textLength)    // 分隔符
-							general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
-							return
-						}
-						// 记账
-						if err := general.WriteFileWithNewLine(pocketFile, localResourcesDesktopFile, writeMode); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-						}
-					}
-
-					// 安装资源文件 - icon 文件
-					archivedResourcesIconFolder := filepath.Join(archivedResourcesFolder, "pixmaps")   // 解压得到的资源文件 - icon 文件夹
-					localResourcesIconFolder := filepath.Join(config.Program.ResourcesPath, "pixmaps") // 本地资源文件 - icon 文件夹
-					if general.FileExist(archivedResourcesIconFolder) {
-						files, err := general.ListFolderFiles(archivedResourcesIconFolder)
-						if err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							color.Print(text)
-							// 分隔符和延时（延时使输出更加顺畅）
-							textLength = general.RealLength(text) // 分隔符长度
-							general.PrintDelimiter(textLength)    // 分隔符
-							general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
-							return
-						}
-						if !general.FileExist(localResourcesIconFolder) {
-							if err := general.CreateDir(localResourcesIconFolder); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
-								return
-							}
-						}
-						for _, file := range files {
-							archivedResourcesIconFile := filepath.Join(archivedResourcesIconFolder, file) // 解压得到的资源文件 - icon 文件
-							localResourcesIconFile := filepath.Join(localResourcesIconFolder, file)       // 本地资源文件 - icon 文件
-							if err := general.Install(archivedResourcesIconFile, localResourcesIconFile, 0644); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
-								continue
-							}
-							// 记账
-							if err := general.WriteFileWithNewLine(pocketFile, localResourcesIconFile, writeMode); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							}
-						}
 					}
+					// 分隔符和延时（延时使输出更加顺畅）
+					textLength = general.RealLength(text) // 分隔符长度
+					general.PrintDelimiter(textLength)    // 分隔符
+					general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
+					return
+				}
+				// 检测本地程序是否存在
+				if commandErr != nil { // 不存在，安装
+					// 本次安装结束分隔符
+					text := color.Sprintf("%s %s %s %s\n", general.SuccessFlag, general.FgGreenText(name), general.FgYellowText(remoteTag), general.FgMagentaText("installed"))
+					color.Print(text)
+					textLength = general.RealLength(text) // 分隔符长度
+				} else { // 存在，更新
 					// 本次更新结束分隔符
 					text := color.Sprintf("%s %s %s %s %s %s\n", general.SuccessFlag, general.FgGreenText(name), general.FgYellowText(localVersion), general.Indicator, general.NoteText(remoteTag), general.FgMagentaText("updated"))
 					color.Print(text)
@@ -436,15 +946,14 @@ func InstallSelfProgram(config *general.Config) {
 				for _, completionDir := range config.Program.Go.CompletionDir {
 					if general.FileExist(completionDir) {
 						completionFile := filepath.Join(completionDir, color.Sprintf("_%s", name))
-						generateArgs := []string{"-c", color.Sprintf("%s completion zsh > %s", localProgram, completionFile)}
-						if _, _, err := general.RunCommandToBuffer("bash", generateArgs); err != nil {
+						if err := installCompletionScript(transaction, localProgram, completionFile); err != nil {
 							text := color.Sprintf("%s %s\n", general.ErrorFlag, general.DangerText(general.AcsInstallFailedMessage))
 							color.Print(text)
 							textLength = general.RealLength(text) // 分隔符长度
 							continue
 						} else {
 							// 记账
-							if err := general.WriteFileWithNewLine(pocketFile, completionFile, writeMode); err != nil {
+							if err := ledger.AddFile(completionFile); err != nil {
 								fileName, lineNo := general.GetCallerInfo()
 								color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 							}
@@ -456,6 +965,31 @@ func InstallSelfProgram(config *general.Config) {
 						}
 					}
 				}
+				// 写入记账文件
+				if err := general.WriteLedger(pocketFile, ledger); err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+					color.Print(text)
+					// 撤销本次安装
+					if err := transaction.Rollback(); err != nil {
+						fileName, lineNo := general.GetCallerInfo()
+						text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+						color.Print(text)
+					}
+					// 分隔符和延时（延时使输出更加顺畅）
+					textLength = general.RealLength(text) // 分隔符长度
+					general.PrintDelimiter(textLength)    // 分隔符
+					general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
+					return
+				}
+				// 提交安装事务，提交后才清理超出保留数量的历史版本
+				if err := transaction.Commit(); err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+				} else if err := general.PruneBackupVersions(pocketFile, config.Program.RollbackKeep); err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+				}
 			} else { // 压缩包校验失败
 				fileName, lineNo := general.GetCallerInfo()
 				text := color.Sprintf("%s %s Archive file verification failed: %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), filesInfo.ArchiveFileInfo.Name)
@@ -474,30 +1008,8 @@ func InstallSelfProgram(config *general.Config) {
 			return
 		}
 
-		// API
-		goGithubLatestSourceTagApi := color.Sprintf(general.GoLatestSourceTagApiFormat, config.Program.Go.GithubApi, config.Program.Go.GithubUsername, name) // 请求远端仓库最新 Tag
-		goGiteaLatestSourceTagApi := color.Sprintf(general.GoLatestSourceTagApiFormat, config.Program.Go.GiteaApi, config.Program.Go.GiteaUsername, name)    // 请求远端仓库最新 Tag
-
-		// 请求 API - GitHub
-		body, err := general.RequestApi(goGithubLatestSourceTagApi)
-		if err != nil {
-			fileName, lineNo := general.GetCallerInfo()
-			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-			// 请求 API - Gitea
-			body, err = general.RequestApi(goGiteaLatestSourceTagApi)
-			if err != nil {
-				fileName, lineNo := general.GetCallerInfo()
-				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-				color.Print(text)
-				// 分隔符和延时（延时使输出更加顺畅）
-				textLength = general.RealLength(text) // 分隔符长度
-				general.PrintDelimiter(textLength)    // 分隔符
-				general.Delay(general.DelayTime)      // 添加一个延时，使输出更加顺畅
-				return
-			}
-		}
-		// 获取远端版本（用于 source 安装方法）
-		remoteTag, err := general.GetLatestSourceTag(body)
+		// 按顺序从各镜像获取远端版本（用于 source 安装方法）
+		remoteTag, err := getGolangSourceTag(config, name, tryForges)
 		if err != nil {
 			fileName, lineNo := general.GetCallerInfo()
 			text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
@@ -513,14 +1025,14 @@ func InstallSelfProgram(config *general.Config) {
 		localVersion, _, commandErr := general.RunCommandToBuffer(localProgram, programVersionArgs)
 
 		// 比较远端和本地版本
-		if remoteTag == localVersion { // 版本一致，则输出无需更新信息
-			text := color.Sprintf("%s %s %s %s\n", general.LatestFlag, general.FgGreenText(name), general.FgYellowText(localVersion), general.LatestVersionMessage)
+		if !golangNeedsUpdate(config, name, remoteTag, localVersion, commandErr == nil) && !force { // 远端版本不比本地版本新，则输出无需更新信息
+			text := color.Sprintf("%s %s %s %s\n", general.LatestFlag, general.FgGreenText(name), general.FgYellowText(localVersion), upToDateMessage(remoteTag, localVersion))
 			color.Print(text)
 			textLength = general.RealLength(text) // 分隔符长度
-		} else { // 版本不一致，则安装或更新程序，并输出已安装/更新信息
-			// 如果 Temp 中已有远端仓库则删除重新克隆
+		} else { // 远端版本更新或未安装，则安装或更新程序，并输出已安装/更新信息
+			// 源码缓存在多次安装之间保留，指定 --clean 时删除重新克隆
 			goSourceTempDir := filepath.Join(config.Program.SourceTemp, name)
-			if general.FileExist(goSourceTempDir) {
+			if clean && general.FileExist(goSourceTempDir) {
 				if err := os.RemoveAll(goSourceTempDir); err != nil {
 					fileName, lineNo := general.GetCallerInfo()
 					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
@@ -532,33 +1044,46 @@ func InstallSelfProgram(config *general.Config) {
 					return
 				}
 			}
-			// 克隆远端仓库 - GitHub
-			goGithubCloneBaseUrl := color.Sprintf("%s/%s", config.Program.Go.GithubUrl, config.Program.Go.GithubUsername) // 远端仓库基础克隆地址（除仓库名）
-			color.Printf("%s %s %s %s ", general.DownloadFlag, general.LightText("Clone"), general.FgGreenText(name), "from GitHub")
-			if err := general.CloneRepoViaHTTP(config.Program.SourceTemp, goGithubCloneBaseUrl, name); err != nil {
-				color.Printf("%s\n", general.DangerText("error -> ", err))
-				// 克隆远端仓库 - Gitea
-				goGiteaCloneBaseUrl := color.Sprintf("%s/%s", config.Program.Go.GiteaUrl, config.Program.Go.GiteaUsername) // 远端仓库基础克隆地址（除仓库名）
-				color.Printf("%s %s %s %s ", general.DownloadFlag, general.LightText("Clone"), general.FgGreenText(name), "from Gitea")
-				if err := general.CloneRepoViaHTTP(config.Program.SourceTemp, goGiteaCloneBaseUrl, name); err != nil {
-					text := color.Sprintf("%s\n", general.DangerText("error -> ", err))
-					color.Print(text)
-					// 分隔符和延时（延时使输出更加顺畅）
-					textLength = general.RealLength(text) // 分隔符长度
-					general.PrintDelimiter(textLength)    // 分隔符
-					general.Delay(0.1)                    // 0.1s
-					return
-				} else {
-					color.Println(general.SuccessText("success"))
-				}
-			} else {
-				color.Println(general.SuccessText("success"))
+			// 按顺序从各镜像克隆远端仓库
+			cloneUrl, err := cloneFromMirrors(config, name, remoteTag) // 实际使用的克隆地址
+			if err != nil {
+				fileName, lineNo := general.GetCallerInfo()
+				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+				color.Print(text)
+				// 分隔符和延时（延时使输出更加顺畅）
+				textLength = general.RealLength(text) // 分隔符长度
+				general.PrintDelimiter(textLength)    // 分隔符
+				general.Delay(0.1)                    // 0.1s
+				return
 			}
 
 			// 进到克隆的远端文件目录
 			if err := general.GoToDir(goSourceTempDir); err != nil {
 				fileName, lineNo := general.GetCallerInfo()
-				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+				color.Print(text)
+				// 分隔符和延时（延时使输出更加顺畅）
+				textLength = general.RealLength(text) // 分隔符长度
+				general.PrintDelimiter(textLength)    // 分隔符
+				general.Delay(0.1)                    // 0.1s
+				return
+			}
+
+			// 按构建配置编译生成程序
+			buildPlan, err := config.Program.BuildPlanOf(name, config.Program.Self.GeneratePath)
+			if err != nil {
+				fileName, lineNo := general.GetCallerInfo()
+				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+				color.Print(text)
+				// 分隔符和延时（延时使输出更加顺畅）
+				textLength = general.RealLength(text) // 分隔符长度
+				general.PrintDelimiter(textLength)    // 分隔符
+				general.Delay(0.1)                    // 0.1s
+				return
+			}
+			if _, stderr, err := general.RunCommandToBufferWithEnv(buildPlan.Command, buildPlan.Args, buildPlan.Env); err != nil {
+				fileName, lineNo := general.GetCallerInfo()
+				text := color.Sprintf("%s %s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err, general.SecondaryText(stderr))
 				color.Print(text)
 				// 分隔符和延时（延时使输出更加顺畅）
 				textLength = general.RealLength(text) // 分隔符长度
@@ -567,10 +1092,12 @@ func InstallSelfProgram(config *general.Config) {
 				return
 			}
 
-			// 编译生成程序
-			if general.FileExist("Makefile") { // Makefile 文件存在则使用 make 编译
-				makeArgs := []string{}
-				if _, _, err := general.RunCommandToBuffer("make", makeArgs); err != nil {
+			// 检测编译生成的程序是否存在
+			compileProgram := filepath.Join(config.Program.SourceTemp, name, buildPlan.Output) // 编译生成的程序
+			if general.FileExist(compileProgram) {
+				// 开始安装事务，失败时撤销本次写入的文件
+				transaction, err := general.BeginTransaction(config.Program.PocketPath, name, pocketFile)
+				if err != nil {
 					fileName, lineNo := general.GetCallerInfo()
 					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 					color.Print(text)
@@ -580,136 +1107,51 @@ func InstallSelfProgram(config *general.Config) {
 					general.Delay(0.1)                    // 0.1s
 					return
 				}
-			} else if general.FileExist("main.go") { // Makefile 文件不存在则使用 `go build` 命令编译
-				buildArgs := []string{"build", "-trimpath", "-ldflags=-s -w", "-o", name}
-				if _, _, err := general.RunCommandToBuffer("go", buildArgs); err != nil {
+				// 更新前在事务中备份当前版本，用于回滚，安装失败时一并撤销
+				if commandErr == nil {
+					if err := general.BackupVersion(transaction, pocketFile, localProgram, localVersion, config.Program.RollbackKeep); err != nil {
+						fileName, lineNo := general.GetCallerInfo()
+						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+						color.Print(text)
+						// 撤销本次安装
+						if err := transaction.Rollback(); err != nil {
+							fileName, lineNo := general.GetCallerInfo()
+							text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+							color.Print(text)
+						}
+						// 分隔符和延时（延时使输出更加顺畅）
+						textLength = general.RealLength(text) // 分隔符长度
+						general.PrintDelimiter(textLength)    // 分隔符
+						general.Delay(0.1)                    // 0.1s
+						return
+					}
+				}
+				// 初始化记账信息
+				ledger := general.NewLedger(name, remoteTag, "source", cloneUrl)
+				// 安装程序
+				if err := installCompiledProgram(transaction, config, buildPlan, compileProgram, localProgram, ledger); err != nil {
 					fileName, lineNo := general.GetCallerInfo()
 					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 					color.Print(text)
+					// 撤销本次安装
+					if err := transaction.Rollback(); err != nil {
+						fileName, lineNo := general.GetCallerInfo()
+						text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+						color.Print(text)
+					}
 					// 分隔符和延时（延时使输出更加顺畅）
 					textLength = general.RealLength(text) // 分隔符长度
 					general.PrintDelimiter(textLength)    // 分隔符
 					general.Delay(0.1)                    // 0.1s
 					return
 				}
-			} else {
-				fileName, lineNo := general.GetCallerInfo()
-				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), general.UnableToCompileMessage)
-				color.Print(text)
-				// 分隔符和延时（延时使输出更加顺畅）
-				textLength = general.RealLength(text) // 分隔符长度
-				general.PrintDelimiter(textLength)    // 分隔符
-				general.Delay(0.1)                    // 0.1s
-				return
-			}
-
-			// 检测编译生成的程序是否存在
-			compileProgram := filepath.Join(config.Program.SourceTemp, name, config.Program.Go.GeneratePath, name) // 编译生成的程序
-			if general.FileExist(compileProgram) {
 				// 检测本地程序是否存在
 				if commandErr != nil { // 不存在，安装
-					if general.FileExist("Makefile") { // Makefile 文件存在则使用 `make install` 命令安装
-						makeArgs := []string{"install"}
-						if _, _, err := general.RunCommandToBuffer("make", makeArgs); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							color.Print(text)
-							// 分隔符和延时（延时使输出更加顺畅）
-							textLength = general.RealLength(text) // 分隔符长度
-							general.PrintDelimiter(textLength)    // 分隔符
-							general.Delay(0.1)                    // 0.1s
-							return
-						}
-					} else { // Makefile 文件不存在则使用自定义函数安装
-						if err := general.Install(compileProgram, localProgram, 0755); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							color.Print(text)
-							// 分隔符和延时（延时使输出更加顺畅）
-							textLength = general.RealLength(text) // 分隔符长度
-							general.PrintDelimiter(textLength)    // 分隔符
-							general.Delay(0.1)                    // 0.1s
-							return
-						} else {
-							// 记账
-							if err := general.WriteFileWithNewLine(pocketFile, localProgram, writeMode); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							}
-
-							// 为已安装的程序设置可执行权限
-							if err := os.Chmod(localProgram, 0755); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(0.1)                    // 0.1s
-								return
-							}
-						}
-					}
 					// 本次安装结束分隔符
 					text := color.Sprintf("%s %s %s %s\n", general.SuccessFlag, general.FgGreenText(name), general.FgYellowText(remoteTag), general.FgMagentaText("installed"))
 					color.Print(text)
 					textLength = general.RealLength(text) // 分隔符长度
 				} else { // 存在，更新
-					if general.FileExist("Makefile") { // Makefile 文件存在则使用 `make install` 命令更新
-						makeArgs := []string{"install"}
-						if _, _, err := general.RunCommandToBuffer("make", makeArgs); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							color.Print(text)
-							// 分隔符和延时（延时使输出更加顺畅）
-							textLength = general.RealLength(text) // 分隔符长度
-							general.PrintDelimiter(textLength)    // 分隔符
-							general.Delay(0.1)                    // 0.1s
-							return
-						}
-					} else { // Makefile 文件不存在则使用自定义函数更新
-						// 删除已安装的旧程序
-						if err := os.Remove(localProgram); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							color.Print(text)
-							// 分隔符和延时（延时使输出更加顺畅）
-							textLength = general.RealLength(text) // 分隔符长度
-							general.PrintDelimiter(textLength)    // 分隔符
-							general.Delay(0.1)                    // 0.1s
-							return
-						}
-
-						// 安装程序
-						if err := general.Install(compileProgram, localProgram, 0755); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							color.Print(text)
-							// 分隔符和延时（延时使输出更加顺畅）
-							textLength = general.RealLength(text) // 分隔符长度
-							general.PrintDelimiter(textLength)    // 分隔符
-							general.Delay(0.1)                    // 0.1s
-							return
-						} else {
-							// 记账
-							if err := general.WriteFileWithNewLine(pocketFile, localProgram, writeMode); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							}
-
-							// 为已安装的程序设置可执行权限
-							if err := os.Chmod(localProgram, 0755); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(0.1)                    // 0.1s
-								return
-							}
-						}
-					}
 					// 本次更新结束分隔符
 					text := color.Sprintf("%s %s %s %s %s %s\n", general.SuccessFlag, general.FgGreenText(name), general.FgYellowText(localVersion), general.Indicator, general.NoteText(remoteTag), general.FgMagentaText("updated"))
 					color.Print(text)
@@ -719,15 +1161,14 @@ func InstallSelfProgram(config *general.Config) {
 				for _, completionDir := range config.Program.Go.CompletionDir {
 					if general.FileExist(completionDir) {
 						completionFile := filepath.Join(completionDir, color.Sprintf("_%s", name))
-						generateArgs := []string{"-c", color.Sprintf("%s completion zsh > %s", localProgram, completionFile)}
-						if _, _, err := general.RunCommandToBuffer("bash", generateArgs); err != nil {
+						if err := installCompletionScript(transaction, localProgram, completionFile); err != nil {
 							text := color.Sprintf("%s %s\n", general.ErrorFlag, general.DangerText(general.AcsInstallFailedMessage))
 							color.Print(text)
 							textLength = general.RealLength(text) // 分隔符长度
 							continue
 						} else {
 							// 记账
-							if err := general.WriteFileWithNewLine(pocketFile, completionFile, writeMode); err != nil {
+							if err := ledger.AddFile(completionFile); err != nil {
 								fileName, lineNo := general.GetCallerInfo()
 								color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 							}
@@ -739,6 +1180,31 @@ func InstallSelfProgram(config *general.Config) {
 						}
 					}
 				}
+				// 写入记账文件
+				if err := general.WriteLedger(pocketFile, ledger); err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+					color.Print(text)
+					// 撤销本次安装
+					if err := transaction.Rollback(); err != nil {
+						fileName, lineNo := general.GetCallerInfo()
+						text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+						color.Print(text)
+					}
+					// 分隔符和延时（延时使输出更加顺畅）
+					textLength = general.RealLength(text) // 分隔符长度
+					general.PrintDelimiter(textLength)    // 分隔符
+					general.Delay(0.1)                    // 0.1s
+					return
+				}
+				// 提交安装事务，提交后才清理超出保留数量的历史版本
+				if err := transaction.Commit(); err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+				} else if err := general.PruneBackupVersions(pocketFile, config.Program.RollbackKeep); err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+				}
 			} else {
 				fileName, lineNo := general.GetCallerInfo()
 				text := color.Sprintf("%s %s Source file %s not found\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), compileProgram)
@@ -760,10 +1226,16 @@ func InstallSelfProgram(config *general.Config) {
 //
 // 参数：
 //   - config: 解析 toml 配置文件得到的配置项
-func InstallGolangBasedProgram(config *general.Config) {
+//   - programs: 通过命令行参数指定的程序名，为空时由用户选择
+//   - allInstalled: 是否选择所有已安装的程序
+//   - force: 版本一致时是否仍然重新安装
+//   - clean: 是否删除源码缓存后重新克隆（用于 source 安装方式）
+func InstallGolangBasedProgram(config *general.Config, programs []string, allInstalled, force, clean bool) {
 	// 设置代理
 	general.SetVariable("http_proxy", config.Variable.HTTPProxy)
 	general.SetVariable("https_proxy", config.Variable.HTTPSProxy)
+	// 设置 API 认证和限流策略
+	general.SetupApiAuth(config)
 
 	// 设置进度条参数
 	general.ProgressParameters["view"] = "1"
@@ -790,8 +1262,12 @@ func InstallGolangBasedProgram(config *general.Config) {
 	negatives.WriteString(color.Sprintf("%s Installed %d/%d \x1b[3m%s\x1b[0m programs\n", general.InfoText("INFO:"), len(installedProgram), totalNum, general.FgCyanText("golang-based")))
 	negatives.WriteString(color.Sprintf("%s Installation path: %s\n", general.InfoText("INFO:"), general.PrimaryText(config.Program.ProgramPath)))
 
-	// 让用户选择需要安装/更新的程序
-	selectedPrograms, err := general.MultipleSelectionFilter(config.Program.Go.Names, installedProgram, negatives.String())
+	// 让用户选择需要安装/更新的程序，并显示每个程序遵循的发布渠道
+	channels := make(map[string]string)
+	for _, program := range config.Program.Go.Names {
+		channels[program] = color.Sprintf("[%s]", config.Program.Go.ChannelOf(program))
+	}
+	selectedPrograms, err := selectPrograms(config.Program.Go.Names, installedProgram, channels, programs, allInstalled, negatives.String())
 	if err != nil {
 		fileName, lineNo := general.GetCallerInfo()
 		color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
@@ -821,12 +1297,9 @@ func InstallGolangBasedProgram(config *general.Config) {
 		for _, program := range selectedPrograms {
 			// 记账文件
 			pocketFile := filepath.Join(config.Program.PocketPath, program, config.Program.PocketFile) // 记账文件路径
-			var writeMode = "a"                                                                        // 写入模式
 
-			// API
-			goGithubLatestReleaseTagApi := color.Sprintf(general.GoLatestReleaseTagApiFormat, config.Program.Go.ReleaseApi, config.Program.Go.GithubUsername, program) // 请求远端仓库最新 Tag
-			// 请求 API - GitHub
-			body, err := general.RequestApi(goGithubLatestReleaseTagApi)
+			// 按顺序从各镜像获取 Release 信息
+			release, forge, err := getGolangRelease(config, program, tryForges)
 			if err != nil {
 				fileName, lineNo := general.GetCallerInfo()
 				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
@@ -838,17 +1311,7 @@ func InstallGolangBasedProgram(config *general.Config) {
 				continue
 			}
 			// 获取远端版本（用于 release 安装方法）
-			remoteTag, err := general.GetLatestReleaseTag(body)
-			if err != nil {
-				fileName, lineNo := general.GetCallerInfo()
-				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-				color.Print(text)
-				// 分隔符和延时（延时使输出更加顺畅）
-				textLength = general.RealLength(text) // 分隔符长度
-				general.PrintDelimiter(textLength)    // 分隔符
-				general.Delay(0.1)                    // 0.1s
-				continue
-			}
+			remoteTag := release.Tag
 
 			// 获取本地程序版本信息
 			localProgram := filepath.Join(config.Program.ProgramPath, program) // 本地程序路径
@@ -856,15 +1319,15 @@ func InstallGolangBasedProgram(config *general.Config) {
 			localVersion, _, commandErr := general.RunCommandToBuffer(localProgram, programVersionArgs)
 
 			// 比较远端和本地版本
-			if remoteTag == localVersion { // 版本一致，则输出无需更新信息
-				text := color.Sprintf("%s %s %s %s\n", general.LatestFlag, general.FgGreenText(program), general.FgYellowText(localVersion), general.LatestVersionMessage)
+			if !golangNeedsUpdate(config, program, remoteTag, localVersion, commandErr == nil) && !force { // 远端版本不比本地版本新，则输出无需更新信息
+				text := color.Sprintf("%s %s %s %s\n", general.LatestFlag, general.FgGreenText(program), general.FgYellowText(localVersion), upToDateMessage(remoteTag, localVersion))
 				color.Print(text)
 				textLength = general.RealLength(text) // 分隔符长度
-			} else { // 版本不一致，则安装或更新程序，并输出已安装/更新信息
-				// 下载远端文件（如果 Temp 中已有远端文件则删除重新下载）
+			} else { // 远端版本更新或未安装，则安装或更新程序，并输出已安装/更新信息
+				// 下载远端文件（如果 Temp 中已有远端文件则删除重新下载，保留未完成的下载以便续传）
 				goReleaseTempDir := filepath.Join(config.Program.ReleaseTemp, program)
 				if general.FileExist(goReleaseTempDir) {
-					if err := os.RemoveAll(goReleaseTempDir); err != nil {
+					if err := general.CleanDownloadDir(goReleaseTempDir); err != nil {
 						fileName, lineNo := general.GetCallerInfo()
 						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 						color.Print(text)
@@ -875,21 +1338,24 @@ func InstallGolangBasedProgram(config *general.Config) {
 						continue
 					}
 				}
-				// 组装需要的文件的名称
-				fileName := general.FileName{}
-				// - checksums.txt
-				fileName.ChecksumsFile = "checksums.txt"
-				// - Archive File
-				fileType := func() string {
-					if general.Platform == "windows" {
-						return "zip"
-					}
-					return "tar.gz"
-				}()
-				archiveFileNameWithoutFileType := color.Sprintf("%s_%s_%s_%s", program, remoteTag, general.Platform, general.Arch)
-				fileName.ArchiveFile = color.Sprintf("%s.%s", archiveFileNameWithoutFileType, fileType)
+				// 根据文件名模板组装需要的文件的名称
+				assetNames, err := config.Program.Go.ReleaseAssets(program, remoteTag)
+				if err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+					color.Print(text)
+					// 分隔符和延时（延时使输出更加顺畅）
+					textLength = general.RealLength(text) // 分隔符长度
+					general.PrintDelimiter(textLength)    // 分隔符
+					general.Delay(0.1)                    // 0.1s
+					continue
+				}
+				fileName := general.FileName{
+					ChecksumsFile: assetNames.Checksums, // 校验文件
+					ArchiveFiles:  assetNames.Archives,  // 各种格式的压缩包或无需解压的可执行文件
+				}
 				// 获取 Release 文件信息
-				filesInfo, err := general.GetReleaseFileInfo(body, fileName)
+				filesInfo, err := general.GetReleaseFileInfo(release, fileName)
 				if err != nil {
 					fileName, lineNo := general.GetCallerInfo()
 					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
@@ -904,9 +1370,19 @@ func InstallGolangBasedProgram(config *general.Config) {
 				general.ProgressParameters["prefix"] = "Download"
 				general.ProgressParameters["project"] = color.Sprintf("[%s]", program)
 				general.ProgressParameters["fileName"] = color.Sprintf("[%s]", filesInfo.ChecksumsFileInfo.Name)
-				general.ProgressParameters["suffix"] = "from github release:"
 				checksumsLocalPath := filepath.Join(config.Program.ReleaseTemp, program, filesInfo.ChecksumsFileInfo.Name) // Checksums 文件本地存储位置
-				if err := general.DownloadFile(filesInfo.ChecksumsFileInfo.DownloadUrl, checksumsLocalPath, general.ProgressParameters); err != nil {
+				if _, err := downloadReleaseFile(config, forge, program, remoteTag, filesInfo.ChecksumsFileInfo, checksumsLocalPath); err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+					color.Print(text)
+					// 分隔符和延时（延时使输出更加顺畅）
+					textLength = general.RealLength(text) // 分隔符长度
+					general.PrintDelimiter(textLength)    // 分隔符
+					general.Delay(0.1)                    // 0.1s
+					continue
+				}
+				// 校验 Checksums 文件的签名，校验通过前不信任其中的任何 Hash
+				if err := verifyChecksumsSignature(config, forge, release, program, checksumsLocalPath); err != nil {
 					fileName, lineNo := general.GetCallerInfo()
 					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 					color.Print(text)
@@ -920,9 +1396,9 @@ func InstallGolangBasedProgram(config *general.Config) {
 				general.ProgressParameters["prefix"] = "Download"
 				general.ProgressParameters["project"] = color.Sprintf("[%s]", program)
 				general.ProgressParameters["fileName"] = color.Sprintf("[%s]", filesInfo.ArchiveFileInfo.Name)
-				general.ProgressParameters["suffix"] = "from github release:"
 				archiveLocalPath := filepath.Join(config.Program.ReleaseTemp, program, filesInfo.ArchiveFileInfo.Name) // Release 文件本地存储位置
-				if err := general.DownloadFile(filesInfo.ArchiveFileInfo.DownloadUrl, archiveLocalPath, general.ProgressParameters); err != nil {
+				archiveUrl, err := downloadReleaseFile(config, forge, program, remoteTag, filesInfo.ArchiveFileInfo, archiveLocalPath)
+				if err != nil {
 					fileName, lineNo := general.GetCallerInfo()
 					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 					color.Print(text)
@@ -956,8 +1432,12 @@ func InstallGolangBasedProgram(config *general.Config) {
 					continue
 				}
 				if verificationResult { // 压缩包校验通过
-					// 解压压缩包
-					if err := general.UnzipFile(archiveLocalPath, goReleaseTempDir); err != nil {
+					// 解压压缩包，无需解压的可执行文件直接安装
+					archivedFolder := filepath.Join(goReleaseTempDir, general.TrimArchiveType(filesInfo.ArchiveFileInfo.Name)) // 解压得到的文件夹
+					archivedProgram := filepath.Join(archivedFolder, assetNames.BinaryPath(filesInfo.ArchiveFileInfo.Name))    // 解压得到的程序
+					if general.IsExecutableFile(archiveLocalPath) {
+						archivedProgram = archiveLocalPath
+					} else if err := general.UnzipFile(archiveLocalPath, goReleaseTempDir); err != nil {
 						fileName, lineNo := general.GetCallerInfo()
 						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 						color.Print(text)
@@ -967,223 +1447,65 @@ func InstallGolangBasedProgram(config *general.Config) {
 						general.Delay(0.1)                    // 0.1s
 						continue
 					}
-					archivedProgram := filepath.Join(goReleaseTempDir, archiveFileNameWithoutFileType, program)             // 解压得到的程序
-					archivedResourcesFolder := filepath.Join(goReleaseTempDir, archiveFileNameWithoutFileType, "resources") // 解压得到的资源文件夹
+					archivedResourcesFolder := filepath.Join(archivedFolder, "resources") // 解压得到的资源文件夹
 
-					// 初始化记账文件
-					general.InitPocketFile(pocketFile)
-					// 检测本地程序是否存在
-					if commandErr != nil { // 不存在，安装
-						// 安装程序
-						if err := general.Install(archivedProgram, localProgram, 0755); err != nil {
+					// 开始安装事务，失败时撤销本次写入的文件
+					transaction, err := general.BeginTransaction(config.Program.PocketPath, program, pocketFile)
+					if err != nil {
+						fileName, lineNo := general.GetCallerInfo()
+						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+						color.Print(text)
+						// 分隔符和延时（延时使输出更加顺畅）
+						textLength = general.RealLength(text) // 分隔符长度
+						general.PrintDelimiter(textLength)    // 分隔符
+						general.Delay(0.1)                    // 0.1s
+						continue
+					}
+					// 更新前在事务中备份当前版本，用于回滚，安装失败时一并撤销
+					if commandErr == nil {
+						if err := general.BackupVersion(transaction, pocketFile, localProgram, localVersion, config.Program.RollbackKeep); err != nil {
 							fileName, lineNo := general.GetCallerInfo()
 							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 							color.Print(text)
-							// 分隔符和延时（延时使输出更加顺畅）
-							textLength = general.RealLength(text) // 分隔符长度
-							general.PrintDelimiter(textLength)    // 分隔符
-							general.Delay(0.1)                    // 0.1s
-							continue
-						} else {
-							// 记账
-							if err := general.WriteFileWithNewLine(pocketFile, localProgram, writeMode); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							}
-
-							// 为已安装的程序设置可执行权限
-							if err := os.Chmod(localProgram, 0755); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(0.1)                    // 0.1s
-								continue
-							}
-						}
-
-						// 安装资源文件 - desktop 文件
-						archivedResourcesDesktopFile := filepath.Join(archivedResourcesFolder, "applications", color.Sprintf("%s.desktop", program))   // 解压得到的资源文件 - desktop 文件
-						localResourcesDesktopFile := filepath.Join(config.Program.ResourcesPath, "applications", color.Sprintf("%s.desktop", program)) // 本地资源文件 - desktop 文件
-						if general.FileExist(archivedResourcesDesktopFile) {
-							if err := general.Install(archivedResourcesDesktopFile, localResourcesDesktopFile, 0644); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(0.1)                    // 0.1s
-								continue
-							}
-							// 记账
-							if err := general.WriteFileWithNewLine(pocketFile, localResourcesDesktopFile, writeMode); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							}
-						}
-
-						// 安装资源文件 - icon 文件
-						archivedResourcesIconFolder := filepath.Join(archivedResourcesFolder, "pixmaps")   // 解压得到的资源文件 - icon 文件夹
-						localResourcesIconFolder := filepath.Join(config.Program.ResourcesPath, "pixmaps") // 本地资源文件 - icon 文件夹
-						if general.FileExist(archivedResourcesIconFolder) {
-							files, err := general.ListFolderFiles(archivedResourcesIconFolder)
-							if err != nil {
+							// 撤销本次安装
+							if err := transaction.Rollback(); err != nil {
 								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+								text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(0.1)                    // 0.1s
-								continue
-							}
-							if !general.FileExist(localResourcesIconFolder) {
-								if err := general.CreateDir(localResourcesIconFolder); err != nil {
-									fileName, lineNo := general.GetCallerInfo()
-									text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-									color.Print(text)
-									// 分隔符和延时（延时使输出更加顺畅）
-									textLength = general.RealLength(text) // 分隔符长度
-									general.PrintDelimiter(textLength)    // 分隔符
-									general.Delay(0.1)                    // 0.1s
-									continue
-								}
-							}
-							for _, file := range files {
-								archivedResourcesIconFile := filepath.Join(archivedResourcesIconFolder, file) // 解压得到的资源文件 - icon 文件
-								localResourcesIconFile := filepath.Join(localResourcesIconFolder, file)       // 本地资源文件 - icon 文件
-								if err := general.Install(archivedResourcesIconFile, localResourcesIconFile, 0644); err != nil {
-									fileName, lineNo := general.GetCallerInfo()
-									text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-									color.Print(text)
-									// 分隔符和延时（延时使输出更加顺畅）
-									textLength = general.RealLength(text) // 分隔符长度
-									general.PrintDelimiter(textLength)    // 分隔符
-									general.Delay(0.1)                    // 0.1s
-									continue
-								}
-								// 记账
-								if err := general.WriteFileWithNewLine(pocketFile, localResourcesIconFile, writeMode); err != nil {
-									fileName, lineNo := general.GetCallerInfo()
-									color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								}
 							}
-						}
-						// 本次安装结束分隔符
-						text := color.Sprintf("%s %s %s %s\n", general.SuccessFlag, general.FgGreenText(program), general.FgYellowText(remoteTag), general.FgMagentaText("installed"))
-						color.Print(text)
-						textLength = general.RealLength(text) // 分隔符长度
-					} else { // 存在，更新
-						if err := os.Remove(localProgram); err != nil { // 删除已安装的旧程序
-							fileName, lineNo := general.GetCallerInfo()
-							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							color.Print(text)
 							// 分隔符和延时（延时使输出更加顺畅）
 							textLength = general.RealLength(text) // 分隔符长度
 							general.PrintDelimiter(textLength)    // 分隔符
 							general.Delay(0.1)                    // 0.1s
 							continue
 						}
-						if err := general.Install(archivedProgram, localProgram, 0755); err != nil { // 安装新程序
+					}
+					// 初始化记账信息
+					ledger := general.NewLedger(program, remoteTag, "release", archiveUrl)
+					// 安装程序和资源文件
+					if err := installReleaseFiles(transaction, config, program, archivedProgram, archivedResourcesFolder, localProgram, ledger); err != nil {
+						fileName, lineNo := general.GetCallerInfo()
+						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+						color.Print(text)
+						// 撤销本次安装
+						if err := transaction.Rollback(); err != nil {
 							fileName, lineNo := general.GetCallerInfo()
-							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+							text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 							color.Print(text)
-							// 分隔符和延时（延时使输出更加顺畅）
-							textLength = general.RealLength(text) // 分隔符长度
-							general.PrintDelimiter(textLength)    // 分隔符
-							general.Delay(0.1)                    // 0.1s
-							continue
-						} else {
-							// 记账
-							if err := general.WriteFileWithNewLine(pocketFile, localProgram, writeMode); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							}
-
-							// 为已安装的程序设置可执行权限
-							if err := os.Chmod(localProgram, 0755); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(0.1)                    // 0.1s
-								continue
-							}
-						}
-
-						// 安装资源文件 - desktop 文件
-						archivedResourcesDesktopFile := filepath.Join(archivedResourcesFolder, "applications", color.Sprintf("%s.desktop", program))   // 解压得到的资源文件 - desktop 文件
-						localResourcesDesktopFile := filepath.Join(config.Program.ResourcesPath, "applications", color.Sprintf("%s.desktop", program)) // 本地资源文件 - desktop 文件
-						if general.FileExist(archivedResourcesDesktopFile) {
-							if err := general.Install(archivedResourcesDesktopFile, localResourcesDesktopFile, 0644); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(0.1)                    // 0.1s
-								continue
-							}
-							// 记账
-							if err := general.WriteFileWithNewLine(pocketFile, localResourcesDesktopFile, writeMode); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-							}
-						}
-
-						// 安装资源文件 - icon 文件
-						archivedResourcesIconFolder := filepath.Join(archivedResourcesFolder, "pixmaps")   // 解压得到的资源文件 - icon 文件夹
-						localResourcesIconFolder := filepath.Join(config.Program.ResourcesPath, "pixmaps") // 本地资源文件 - icon 文件夹
-						if general.FileExist(archivedResourcesIconFolder) {
-							files, err := general.ListFolderFiles(archivedResourcesIconFolder)
-							if err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(0.1)                    // 0.1s
-								continue
-							}
-							if !general.FileExist(localResourcesIconFolder) {
-								if err := general.CreateDir(localResourcesIconFolder); err != nil {
-									fileName, lineNo := general.GetCallerInfo()
-									text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-									color.Print(text)
-									// 分隔符和延时（延时使输出更加顺畅）
-									textLength = general.RealLength(text) // 分隔符长度
-									general.PrintDelimiter(textLength)    // 分隔符
-									general.Delay(0.1)                    // 0.1s
-									continue
-								}
-							}
-							for _, file := range files {
-								archivedResourcesIconFile := filepath.Join(archivedResourcesIconFolder, file) // 解压得到的资源文件 - icon 文件
-								localResourcesIconFile := filepath.Join(localResourcesIconFolder, file)       // 本地资源文件 - icon 文件
-								if err := general.Install(archivedResourcesIconFile, localResourcesIconFile, 0644); err != nil {
-									fileName, lineNo := general.GetCallerInfo()
-									text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-									color.Print(text)
-									// 分隔符和延时（延时使输出更加顺畅）
-									textLength = general.RealLength(text) // 分隔符长度
-									general.PrintDelimiter(textLength)    // 分隔符
-									general.Delay(0.1)                    // 0.1s
-									continue
-								}
-								// 记账
-								if err := general.WriteFileWithNewLine(pocketFile, localResourcesIconFile, writeMode); err != nil {
-									fileName, lineNo := general.GetCallerInfo()
-									color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								}
-							}
 						}
+						// 分隔符和延时（延时使输出更加顺畅）
+						textLength = general.RealLength(text) // 分隔符长度
+						general.PrintDelimiter(textLength)    // 分隔符
+						general.Delay(0.1)                    // 0.1s
+						continue
+					}
+					// 检测本地程序是否存在
+					if commandErr != nil { // 不存在，安装
+						// 本次安装结束分隔符
+						text := color.Sprintf("%s %s %s %s\n", general.SuccessFlag, general.FgGreenText(program), general.FgYellowText(remoteTag), general.FgMagentaText("installed"))
+						color.Print(text)
+						textLength = general.RealLength(text) // 分隔符长度
+					} else { // 存在，更新
 						// 本次更新结束分隔符
 						text := color.Sprintf("%s %s %s %s %s %s\n", general.SuccessFlag, general.FgGreenText(program), general.FgYellowText(localVersion), general.Indicator, general.NoteText(remoteTag), general.FgMagentaText("updated"))
 						color.Print(text)
@@ -1193,15 +1515,14 @@ func InstallGolangBasedProgram(config *general.Config) {
 					for _, completionDir := range config.Program.Go.CompletionDir {
 						if general.FileExist(completionDir) {
 							completionFile := filepath.Join(completionDir, color.Sprintf("_%s", program))
-							generateArgs := []string{"-c", color.Sprintf("%s completion zsh > %s", localProgram, completionFile)}
-							if _, _, err := general.RunCommandToBuffer("bash", generateArgs); err != nil {
+							if err := installCompletionScript(transaction, localProgram, completionFile); err != nil {
 								text := color.Sprintf("%s %s\n", general.ErrorFlag, general.DangerText(general.AcsInstallFailedMessage))
 								color.Print(text)
 								textLength = general.RealLength(text) // 分隔符长度
 								continue
 							} else {
 								// 记账
-								if err := general.WriteFileWithNewLine(pocketFile, completionFile, writeMode); err != nil {
+								if err := ledger.AddFile(completionFile); err != nil {
 									fileName, lineNo := general.GetCallerInfo()
 									color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 								}
@@ -1213,6 +1534,31 @@ func InstallGolangBasedProgram(config *general.Config) {
 							}
 						}
 					}
+					// 写入记账文件
+					if err := general.WriteLedger(pocketFile, ledger); err != nil {
+						fileName, lineNo := general.GetCallerInfo()
+						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+						color.Print(text)
+						// 撤销本次安装
+						if err := transaction.Rollback(); err != nil {
+							fileName, lineNo := general.GetCallerInfo()
+							text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+							color.Print(text)
+						}
+						// 分隔符和延时（延时使输出更加顺畅）
+						textLength = general.RealLength(text) // 分隔符长度
+						general.PrintDelimiter(textLength)    // 分隔符
+						general.Delay(0.1)                    // 0.1s
+						continue
+					}
+					// 提交安装事务，提交后才清理超出保留数量的历史版本
+					if err := transaction.Commit(); err != nil {
+						fileName, lineNo := general.GetCallerInfo()
+						color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+					} else if err := general.PruneBackupVersions(pocketFile, config.Program.RollbackKeep); err != nil {
+						fileName, lineNo := general.GetCallerInfo()
+						color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+					}
 				} else { // 压缩包校验失败
 					fileName, lineNo := general.GetCallerInfo()
 					text := color.Sprintf("%s %s Archive file verification failed: %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), filesInfo.ArchiveFileInfo.Name)
@@ -1236,31 +1582,9 @@ func InstallGolangBasedProgram(config *general.Config) {
 		for _, program := range selectedPrograms {
 			// 记账文件
 			pocketFile := filepath.Join(config.Program.PocketPath, program, config.Program.PocketFile) // 记账文件路径
-			var writeMode = "a"                                                                        // 写入模式
 
-			// API
-			goGithubLatestSourceTagApi := color.Sprintf(general.GoLatestSourceTagApiFormat, config.Program.Go.GithubApi, config.Program.Go.GithubUsername, program) // 请求远端仓库最新 Tag
-			goGiteaLatestSourceTagApi := color.Sprintf(general.GoLatestSourceTagApiFormat, config.Program.Go.GiteaApi, config.Program.Go.GiteaUsername, program)    // 请求远端仓库最新 Tag
-			// 请求 API - GitHub
-			body, err := general.RequestApi(goGithubLatestSourceTagApi)
-			if err != nil {
-				fileName, lineNo := general.GetCallerInfo()
-				color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-				// 请求 API - Gitea
-				body, err = general.RequestApi(goGiteaLatestSourceTagApi)
-				if err != nil {
-					fileName, lineNo := general.GetCallerInfo()
-					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-					color.Print(text)
-					// 分隔符和延时（延时使输出更加顺畅）
-					textLength = general.RealLength(text) // 分隔符长度
-					general.PrintDelimiter(textLength)    // 分隔符
-					general.Delay(0.1)                    // 0.1s
-					continue
-				}
-			}
-			// 获取远端版本（用于 source 安装方法）
-			remoteTag, err := general.GetLatestSourceTag(body)
+			// 按顺序从各镜像获取远端版本（用于 source 安装方法）
+			remoteTag, err := getGolangSourceTag(config, program, tryForges)
 			if err != nil {
 				fileName, lineNo := general.GetCallerInfo()
 				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
@@ -1278,14 +1602,14 @@ func InstallGolangBasedProgram(config *general.Config) {
 			localVersion, _, commandErr := general.RunCommandToBuffer(localProgram, programVersionArgs)
 
 			// 比较远端和本地版本
-			if remoteTag == localVersion { // 版本一致，则输出无需更新信息
-				text := color.Sprintf("%s %s %s %s\n", general.LatestFlag, general.FgGreenText(program), general.FgYellowText(localVersion), general.LatestVersionMessage)
+			if !golangNeedsUpdate(config, program, remoteTag, localVersion, commandErr == nil) && !force { // 远端版本不比本地版本新，则输出无需更新信息
+				text := color.Sprintf("%s %s %s %s\n", general.LatestFlag, general.FgGreenText(program), general.FgYellowText(localVersion), upToDateMessage(remoteTag, localVersion))
 				color.Print(text)
 				textLength = general.RealLength(text) // 分隔符长度
-			} else { // 版本不一致，则安装或更新程序，并输出已安装/更新信息
-				// 如果 Temp 中已有远端仓库则删除重新克隆
+			} else { // 远端版本更新或未安装，则安装或更新程序，并输出已安装/更新信息
+				// 源码缓存在多次安装之间保留，指定 --clean 时删除重新克隆
 				goSourceTempDir := filepath.Join(config.Program.SourceTemp, program)
-				if general.FileExist(goSourceTempDir) {
+				if clean && general.FileExist(goSourceTempDir) {
 					if err := os.RemoveAll(goSourceTempDir); err != nil {
 						fileName, lineNo := general.GetCallerInfo()
 						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
@@ -1297,27 +1621,17 @@ func InstallGolangBasedProgram(config *general.Config) {
 						continue
 					}
 				}
-				// 克隆远端仓库 - GitHub
-				goGithubCloneBaseUrl := color.Sprintf("%s/%s", config.Program.Go.GithubUrl, config.Program.Go.GithubUsername) // 远端仓库基础克隆地址（除仓库名）
-				color.Printf("%s %s %s %s ", general.DownloadFlag, general.LightText("Clone"), general.FgGreenText(program), "from GitHub")
-				if err := general.CloneRepoViaHTTP(config.Program.SourceTemp, goGithubCloneBaseUrl, program); err != nil {
-					color.Printf("%s\n", general.DangerText("error -> ", err))
-					// 克隆远端仓库 - Gitea
-					goGiteaCloneBaseUrl := color.Sprintf("%s/%s", config.Program.Go.GiteaUrl, config.Program.Go.GiteaUsername) // 远端仓库基础克隆地址（除仓库名）
-					color.Printf("%s %s %s %s ", general.DownloadFlag, general.LightText("Clone"), general.FgGreenText(program), "from Gitea")
-					if err := general.CloneRepoViaHTTP(config.Program.SourceTemp, goGiteaCloneBaseUrl, program); err != nil {
-						text := color.Sprintf("%s\n", general.DangerText("error -> ", err))
-						color.Print(text)
-						// 分隔符和延时（延时使输出更加顺畅）
-						textLength = general.RealLength(text) // 分隔符长度
-						general.PrintDelimiter(textLength)    // 分隔符
-						general.Delay(0.1)                    // 0.1s
-						continue
-					} else {
-						color.Println(general.SuccessText("success"))
-					}
-				} else {
-					color.Println(general.SuccessText("success"))
+				// 按顺序从各镜像克隆远端仓库
+				cloneUrl, err := cloneFromMirrors(config, program, remoteTag) // 实际使用的克隆地址
+				if err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+					color.Print(text)
+					// 分隔符和延时（延时使输出更加顺畅）
+					textLength = general.RealLength(text) // 分隔符长度
+					general.PrintDelimiter(textLength)    // 分隔符
+					general.Delay(0.1)                    // 0.1s
+					continue
 				}
 				// 进到下载的远端文件目录
 				if err := general.GoToDir(goSourceTempDir); err != nil {
@@ -1330,10 +1644,35 @@ func InstallGolangBasedProgram(config *general.Config) {
 					general.Delay(0.1)                    // 0.1s
 					continue
 				}
-				// 编译生成程序
-				if general.FileExist("Makefile") { // Makefile 文件存在则使用 make 编译
-					makeArgs := []string{}
-					if _, _, err := general.RunCommandToBuffer("make", makeArgs); err != nil {
+				// 按构建配置编译生成程序
+				buildPlan, err := config.Program.BuildPlanOf(program, config.Program.Go.GeneratePath)
+				if err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+					color.Print(text)
+					// 分隔符和延时（延时使输出更加顺畅）
+					textLength = general.RealLength(text) // 分隔符长度
+					general.PrintDelimiter(textLength)    // 分隔符
+					general.Delay(0.1)                    // 0.1s
+					continue
+				}
+				if _, stderr, err := general.RunCommandToBufferWithEnv(buildPlan.Command, buildPlan.Args, buildPlan.Env); err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					text := color.Sprintf("%s %s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err, general.SecondaryText(stderr))
+					color.Print(text)
+					// 分隔符和延时（延时使输出更加顺畅）
+					textLength = general.RealLength(text) // 分隔符长度
+					general.PrintDelimiter(textLength)    // 分隔符
+					general.Delay(0.1)                    // 0.1s
+					continue
+				}
+
+				// 检测编译生成的程序是否存在
+				compileProgram := filepath.Join(config.Program.SourceTemp, program, buildPlan.Output) // 编译生成的程序
+				if general.FileExist(compileProgram) {
+					// 开始安装事务，失败时撤销本次写入的文件
+					transaction, err := general.BeginTransaction(config.Program.PocketPath, program, pocketFile)
+					if err != nil {
 						fileName, lineNo := general.GetCallerInfo()
 						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 						color.Print(text)
@@ -1343,135 +1682,51 @@ func InstallGolangBasedProgram(config *general.Config) {
 						general.Delay(0.1)                    // 0.1s
 						continue
 					}
-				} else if general.FileExist("main.go") { // Makefile 文件不存在则使用 `go build` 命令编译
-					buildArgs := []string{"build", "-trimpath", "-ldflags=-s -w", "-o", program}
-					if _, _, err := general.RunCommandToBuffer("go", buildArgs); err != nil {
+					// 更新前在事务中备份当前版本，用于回滚，安装失败时一并撤销
+					if commandErr == nil {
+						if err := general.BackupVersion(transaction, pocketFile, localProgram, localVersion, config.Program.RollbackKeep); err != nil {
+							fileName, lineNo := general.GetCallerInfo()
+							text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+							color.Print(text)
+							// 撤销本次安装
+							if err := transaction.Rollback(); err != nil {
+								fileName, lineNo := general.GetCallerInfo()
+								text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+								color.Print(text)
+							}
+							// 分隔符和延时（延时使输出更加顺畅）
+							textLength = general.RealLength(text) // 分隔符长度
+							general.PrintDelimiter(textLength)    // 分隔符
+							general.Delay(0.1)                    // 0.1s
+							continue
+						}
+					}
+					// 初始化记账信息
+					ledger := general.NewLedger(program, remoteTag, "source", cloneUrl)
+					// 安装程序
+					if err := installCompiledProgram(transaction, config, buildPlan, compileProgram, localProgram, ledger); err != nil {
 						fileName, lineNo := general.GetCallerInfo()
 						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 						color.Print(text)
+						// 撤销本次安装
+						if err := transaction.Rollback(); err != nil {
+							fileName, lineNo := general.GetCallerInfo()
+							text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+							color.Print(text)
+						}
 						// 分隔符和延时（延时使输出更加顺畅）
 						textLength = general.RealLength(text) // 分隔符长度
 						general.PrintDelimiter(textLength)    // 分隔符
 						general.Delay(0.1)                    // 0.1s
 						continue
 					}
-				} else {
-					fileName, lineNo := general.GetCallerInfo()
-					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), general.UnableToCompileMessage)
-					color.Print(text)
-					// 分隔符和延时（延时使输出更加顺畅）
-					textLength = general.RealLength(text) // 分隔符长度
-					general.PrintDelimiter(textLength)    // 分隔符
-					general.Delay(0.1)                    // 0.1s
-					continue
-				}
-
-				// 检测编译生成的程序是否存在
-				compileProgram := filepath.Join(config.Program.SourceTemp, program, config.Program.Go.GeneratePath, program) // 编译生成的程序
-				if general.FileExist(compileProgram) {
-					// 初始化记账文件
-					general.InitPocketFile(pocketFile)
 					// 检测本地程序是否存在
 					if commandErr != nil { // 不存在，安装
-						if general.FileExist("Makefile") { // Makefile 文件存在则使用 `make install` 命令安装
-							makeArgs := []string{"install"}
-							if _, _, err := general.RunCommandToBuffer("make", makeArgs); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(0.1)                    // 0.1s
-								continue
-							}
-						} else { // Makefile 文件不存在则使用自定义函数安装
-							if err := general.Install(compileProgram, localProgram, 0755); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(0.1)                    // 0.1s
-								continue
-							} else {
-								// 记账
-								if err := general.WriteFileWithNewLine(pocketFile, localProgram, writeMode); err != nil {
-									fileName, lineNo := general.GetCallerInfo()
-									color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								}
-
-								// 为已安装的程序设置可执行权限
-								if err := os.Chmod(localProgram, 0755); err != nil {
-									fileName, lineNo := general.GetCallerInfo()
-									text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-									color.Print(text)
-									// 分隔符和延时（延时使输出更加顺畅）
-									textLength = general.RealLength(text) // 分隔符长度
-									general.PrintDelimiter(textLength)    // 分隔符
-									general.Delay(0.1)                    // 0.1s
-									continue
-								}
-							}
-						}
 						// 本次安装结束分隔符
 						text := color.Sprintf("%s %s %s %s\n", general.SuccessFlag, general.FgGreenText(program), general.FgYellowText(remoteTag), general.FgMagentaText("installed"))
 						color.Print(text)
 						textLength = general.RealLength(text) // 分隔符长度
 					} else { // 存在，更新
-						if general.FileExist("Makefile") { // Makefile 文件存在则使用 `make install` 命令更新
-							makeArgs := []string{"install"}
-							if _, _, err := general.RunCommandToBuffer("make", makeArgs); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(0.1)                    // 0.1s
-								continue
-							}
-						} else { // Makefile 文件不存在则使用自定义函数更新
-							if err := os.Remove(localProgram); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(0.1)                    // 0.1s
-								continue
-							}
-							if err := general.Install(compileProgram, localProgram, 0755); err != nil {
-								fileName, lineNo := general.GetCallerInfo()
-								text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								color.Print(text)
-								// 分隔符和延时（延时使输出更加顺畅）
-								textLength = general.RealLength(text) // 分隔符长度
-								general.PrintDelimiter(textLength)    // 分隔符
-								general.Delay(0.1)                    // 0.1s
-								continue
-							} else {
-								// 记账
-								if err := general.WriteFileWithNewLine(pocketFile, localProgram, writeMode); err != nil {
-									fileName, lineNo := general.GetCallerInfo()
-									color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-								}
-
-								// 为已安装的程序设置可执行权限
-								if err := os.Chmod(localProgram, 0755); err != nil {
-									fileName, lineNo := general.GetCallerInfo()
-									text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-									color.Print(text)
-									// 分隔符和延时（延时使输出更加顺畅）
-									textLength = general.RealLength(text) // 分隔符长度
-									general.PrintDelimiter(textLength)    // 分隔符
-									general.Delay(0.1)                    // 0.1s
-									continue
-								}
-							}
-						}
 						// 本次更新结束分隔符
 						text := color.Sprintf("%s %s %s %s %s %s\n", general.SuccessFlag, general.FgGreenText(program), general.FgYellowText(localVersion), general.Indicator, general.NoteText(remoteTag), general.FgMagentaText("updated"))
 						color.Print(text)
@@ -1481,15 +1736,14 @@ func InstallGolangBasedProgram(config *general.Config) {
 					for _, completionDir := range config.Program.Go.CompletionDir {
 						if general.FileExist(completionDir) {
 							completionFile := filepath.Join(completionDir, color.Sprintf("_%s", program))
-							generateArgs := []string{"-c", color.Sprintf("%s completion zsh > %s", localProgram, completionFile)}
-							if _, _, err := general.RunCommandToBuffer("bash", generateArgs); err != nil {
+							if err := installCompletionScript(transaction, localProgram, completionFile); err != nil {
 								text := color.Sprintf("%s %s\n", general.ErrorFlag, general.DangerText(general.AcsInstallFailedMessage))
 								color.Print(text)
 								textLength = general.RealLength(text) // 分隔符长度
 								continue
 							} else {
 								// 记账
-								if err := general.WriteFileWithNewLine(pocketFile, completionFile, writeMode); err != nil {
+								if err := ledger.AddFile(completionFile); err != nil {
 									fileName, lineNo := general.GetCallerInfo()
 									color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 								}
@@ -1501,6 +1755,31 @@ func InstallGolangBasedProgram(config *general.Config) {
 							}
 						}
 					}
+					// 写入记账文件
+					if err := general.WriteLedger(pocketFile, ledger); err != nil {
+						fileName, lineNo := general.GetCallerInfo()
+						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+						color.Print(text)
+						// 撤销本次安装
+						if err := transaction.Rollback(); err != nil {
+							fileName, lineNo := general.GetCallerInfo()
+							text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+							color.Print(text)
+						}
+						// 分隔符和延时（延时使输出更加顺畅）
+						textLength = general.RealLength(text) // 分隔符长度
+						general.PrintDelimiter(textLength)    // 分隔符
+						general.Delay(0.1)                    // 0.1s
+						continue
+					}
+					// 提交安装事务，提交后才清理超出保留数量的历史版本
+					if err := transaction.Commit(); err != nil {
+						fileName, lineNo := general.GetCallerInfo()
+						color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+					} else if err := general.PruneBackupVersions(pocketFile, config.Program.RollbackKeep); err != nil {
+						fileName, lineNo := general.GetCallerInfo()
+						color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+					}
 				} else {
 					fileName, lineNo := general.GetCallerInfo()
 					text := color.Sprintf("%s %s Source file %s not found\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), compileProgram)
@@ -1523,10 +1802,15 @@ func InstallGolangBasedProgram(config *general.Config) {
 //
 // 参数：
 //   - config: 解析 toml 配置文件得到的配置项
-func InstallShellBasedProgram(config *general.Config) {
+//   - programs: 通过命令行参数指定的脚本名，为空时由用户选择
+//   - allInstalled: 是否选择所有已安装的脚本
+//   - force: Hash 一致时是否仍然重新安装
+func InstallShellBasedProgram(config *general.Config, programs []string, allInstalled, force bool) {
 	// 设置代理
 	general.SetVariable("http_proxy", config.Variable.HTTPProxy)
 	general.SetVariable("https_proxy", config.Variable.HTTPSProxy)
+	// 设置 API 认证和限流策略
+	general.SetupApiAuth(config)
 
 	// 设置进度条参数
 	general.ProgressParameters["view"] = "0"
@@ -1560,7 +1844,7 @@ func InstallShellBasedProgram(config *general.Config) {
 	}
 
 	// 让用户选择需要安装/更新的程序
-	selectedPrograms, err := general.MultipleSelectionFilter(config.Program.Shell.Names, installedProgram, negatives.String())
+	selectedPrograms, err := selectPrograms(config.Program.Shell.Names, installedProgram, nil, programs, allInstalled, negatives.String())
 	if err != nil {
 		fileName, lineNo := general.GetCallerInfo()
 		color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
@@ -1580,31 +1864,9 @@ func InstallShellBasedProgram(config *general.Config) {
 	for _, program := range selectedPrograms {
 		// 记账文件
 		pocketFile := filepath.Join(config.Program.PocketPath, program, config.Program.PocketFile) // 记账文件路径
-		var writeMode = "a"                                                                        // 写入模式
 
-		// API
-		shellGithubLatestHashApi := color.Sprintf(general.ShellLatestHashApiFormat, config.Program.Shell.GithubApi, config.Program.Shell.GithubUsername, config.Program.Shell.Repo, config.Program.Shell.Dir, program) // 请求远端仓库最新脚本的 Hash 值
-		shellGiteaLatestHashApi := color.Sprintf(general.ShellLatestHashApiFormat, config.Program.Shell.GiteaApi, config.Program.Shell.GiteaUsername, config.Program.Shell.Repo, config.Program.Shell.Dir, program)    // 请求远端仓库最新脚本的 Hash 值
-		// 请求 API - GitHub
-		body, err := general.RequestApi(shellGithubLatestHashApi)
-		if err != nil {
-			fileName, lineNo := general.GetCallerInfo()
-			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-			// 请求 API - Gitea
-			body, err = general.RequestApi(shellGiteaLatestHashApi)
-			if err != nil {
-				fileName, lineNo := general.GetCallerInfo()
-				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-				color.Print(text)
-				// 分隔符和延时（延时使输出更加顺畅）
-				textLength = general.RealLength(text) // 分隔符长度
-				general.PrintDelimiter(textLength)    // 分隔符
-				general.Delay(0.1)                    // 0.1s
-				continue
-			}
-		}
-		// 获取远端脚本 Hash
-		remoteHash, err := general.GetLatestSourceHash(body)
+		// 按顺序从各镜像获取远端脚本 Hash
+		remoteHash, forge, err := getShellSourceHash(config, program, tryForges)
 		if err != nil {
 			fileName, lineNo := general.GetCallerInfo()
 			text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
@@ -1622,24 +1884,33 @@ func InstallShellBasedProgram(config *general.Config) {
 		localHash, _, commandErr := general.RunCommandToBuffer("git", programVersionArgs)
 
 		// 比较远端和本地脚本 Hash
-		if remoteHash == localHash { // Hash 一致，则输出无需更新信息
+		if remoteHash == localHash && !force { // Hash 一致，则输出无需更新信息
 			text := color.Sprintf("%s %s %s\n", general.LatestFlag, general.FgGreenText(program), general.LatestVersionMessage)
 			color.Print(text)
 			textLength = general.RealLength(text) // 分隔符长度
 		} else { // Hash 不一致，则更新脚本，并输出已更新信息
 			shellUrlFile := filepath.Join(config.Program.Shell.Dir, program)                                // 脚本在仓库中的路径
 			scriptLocalPath := filepath.Join(config.Program.SourceTemp, config.Program.Shell.Repo, program) // 脚本本地存储位置
-			// 下载远端脚本 - GitHub
-			shellGithubBaseDownloadUrl := color.Sprintf(general.ShellGithubBaseDownloadUrlFormat, config.Program.Shell.GithubRaw, config.Program.Shell.GithubUsername, config.Program.Shell.Repo, config.Program.Shell.GithubBranch) // 脚本远端仓库基础地址
-			fileUrl := color.Sprintf("%s/%s", shellGithubBaseDownloadUrl, shellUrlFile)
-			if err := general.DownloadFile(fileUrl, scriptLocalPath, general.ProgressParameters); err != nil {
+			// 按顺序从各镜像下载远端脚本，优先使用获取脚本 Hash 的镜像
+			fileUrl := "" // 实际使用的下载地址
+			if _, err := tryForges(general.PreferMirror(config.Program.Mirrors, forge.Mirror()), func(forge general.Forge) error {
+				fileUrl = forge.RawFileUrl(config.Program.Shell.Repo, shellUrlFile)
+				return general.DownloadFile(fileUrl, scriptLocalPath, general.ProgressParameters)
+			}); err != nil {
 				fileName, lineNo := general.GetCallerInfo()
 				text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 				color.Print(text)
-				// 下载远端脚本 - Gitea
-				shellGiteaBaseDownloadUrl := color.Sprintf(general.ShellGiteaBaseDownloadUrlFormat, config.Program.Shell.GiteaRaw, config.Program.Shell.GiteaUsername, config.Program.Shell.Repo, config.Program.Shell.GiteaBranch) // 脚本远端仓库基础地址
-				fileUrl := color.Sprintf("%s/%s", shellGiteaBaseDownloadUrl, shellUrlFile)
-				if err = general.DownloadFile(fileUrl, scriptLocalPath, general.ProgressParameters); err != nil {
+				// 分隔符和延时（延时使输出更加顺畅）
+				textLength = general.RealLength(text) // 分隔符长度
+				general.PrintDelimiter(textLength)    // 分隔符
+				general.Delay(0.1)                    // 0.1s
+				continue
+			}
+			// 检测脚本文件是否存在
+			if general.FileExist(scriptLocalPath) {
+				// 开始安装事务，失败时撤销本次写入的文件
+				transaction, err := general.BeginTransaction(config.Program.PocketPath, program, pocketFile)
+				if err != nil {
 					fileName, lineNo := general.GetCallerInfo()
 					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 					color.Print(text)
@@ -1649,77 +1920,80 @@ func InstallShellBasedProgram(config *general.Config) {
 					general.Delay(0.1)                    // 0.1s
 					continue
 				}
-			}
-			// 检测脚本文件是否存在
-			if general.FileExist(scriptLocalPath) {
-				// 初始化记账文件
-				general.InitPocketFile(pocketFile)
-				// 检测本地程序是否存在
-				if commandErr != nil { // 不存在，安装
-					if err := general.Install(scriptLocalPath, localProgram, 0755); err != nil {
+				// 更新前在事务中备份当前版本，用于回滚，安装失败时一并撤销
+				if commandErr == nil {
+					if err := general.BackupVersion(transaction, pocketFile, localProgram, localHash, config.Program.RollbackKeep); err != nil {
 						fileName, lineNo := general.GetCallerInfo()
 						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 						color.Print(text)
-						// 分隔符和延时（延时使输出更加顺畅）
-						textLength = general.RealLength(text) // 分隔符长度
-						general.PrintDelimiter(textLength)    // 分隔符
-						general.Delay(0.1)                    // 0.1s
-						continue
-					} else {
-						// 记账
-						if err := general.WriteFileWithNewLine(pocketFile, localProgram, writeMode); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-						}
-
-						// 为已安装的脚本设置可执行权限
-						if err := os.Chmod(localProgram, 0755); err != nil {
+						// 撤销本次安装
+						if err := transaction.Rollback(); err != nil {
 							fileName, lineNo := general.GetCallerInfo()
-							color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+							text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+							color.Print(text)
 						}
-						text := color.Sprintf("%s %s %s %s\n", general.SuccessFlag, general.FgGreenText(program), general.FgYellowText(remoteHash[:6]), general.FgMagentaText("installed"))
-						color.Print(text)
-						textLength = general.RealLength(text) // 分隔符长度
-					}
-				} else { // 存在，更新
-					// 删除已安装的旧程序
-					if err := os.Remove(localProgram); err != nil {
-						fileName, lineNo := general.GetCallerInfo()
-						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-						color.Print(text)
 						// 分隔符和延时（延时使输出更加顺畅）
 						textLength = general.RealLength(text) // 分隔符长度
 						general.PrintDelimiter(textLength)    // 分隔符
 						general.Delay(0.1)                    // 0.1s
 						continue
 					}
-
-					// 安装程序
-					if err := general.Install(scriptLocalPath, localProgram, 0755); err != nil {
+				}
+				// 初始化记账信息
+				ledger := general.NewLedger(program, remoteHash, "script", fileUrl)
+				// 安装脚本
+				if err := installProgramFile(transaction, scriptLocalPath, localProgram, ledger); err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+					color.Print(text)
+					// 撤销本次安装
+					if err := transaction.Rollback(); err != nil {
 						fileName, lineNo := general.GetCallerInfo()
-						text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+						text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 						color.Print(text)
-						// 分隔符和延时（延时使输出更加顺畅）
-						textLength = general.RealLength(text) // 分隔符长度
-						general.PrintDelimiter(textLength)    // 分隔符
-						general.Delay(0.1)                    // 0.1s
-						continue
-					} else {
-						// 记账
-						if err := general.WriteFileWithNewLine(pocketFile, localProgram, writeMode); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-						}
-
-						// 为已更新的脚本设置可执行权限
-						if err := os.Chmod(localProgram, 0755); err != nil {
-							fileName, lineNo := general.GetCallerInfo()
-							color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-						}
-						text := color.Sprintf("%s %s %s %s %s %s\n", general.SuccessFlag, general.FgGreenText(program), general.FgYellowText(localHash[:6]), general.Indicator, general.NoteText(remoteHash[:6]), general.FgMagentaText("updated"))
+					}
+					// 分隔符和延时（延时使输出更加顺畅）
+					textLength = general.RealLength(text) // 分隔符长度
+					general.PrintDelimiter(textLength)    // 分隔符
+					general.Delay(0.1)                    // 0.1s
+					continue
+				}
+				// 检测本地程序是否存在
+				if commandErr != nil { // 不存在，安装
+					// 本次安装结束分隔符
+					text := color.Sprintf("%s %s %s %s\n", general.SuccessFlag, general.FgGreenText(program), general.FgYellowText(remoteHash[:6]), general.FgMagentaText("installed"))
+					color.Print(text)
+					textLength = general.RealLength(text) // 分隔符长度
+				} else { // 存在，更新
+					// 本次更新结束分隔符
+					text := color.Sprintf("%s %s %s %s %s %s\n", general.SuccessFlag, general.FgGreenText(program), general.FgYellowText(localHash[:6]), general.Indicator, general.NoteText(remoteHash[:6]), general.FgMagentaText("updated"))
+					color.Print(text)
+					textLength = general.RealLength(text) // 分隔符长度
+				}
+				// 写入记账文件
+				if err := general.WriteLedger(pocketFile, ledger); err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+					color.Print(text)
+					// 撤销本次安装
+					if err := transaction.Rollback(); err != nil {
+						fileName, lineNo := general.GetCallerInfo()
+						text = color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 						color.Print(text)
-						textLength = general.RealLength(text) // 分隔符长度
 					}
+					// 分隔符和延时（延时使输出更加顺畅）
+					textLength = general.RealLength(text) // 分隔符长度
+					general.PrintDelimiter(textLength)    // 分隔符
+					general.Delay(0.1)                    // 0.1s
+					continue
+				}
+				// 提交安装事务，提交后才清理超出保留数量的历史版本
+				if err := transaction.Commit(); err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+				} else if err := general.PruneBackupVersions(pocketFile, config.Program.RollbackKeep); err != nil {
+					fileName, lineNo := general.GetCallerInfo()
+					color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 				}
 			} else {
 				fileName, lineNo := general.GetCallerInfo()
@@ -0,0 +1,328 @@
+/*
+File: list.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-17 19:30:12
+
+Description: 子命令 'list' 的实现
+*/
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/yhyj/manager/general"
+)
+
+// programStatus 单个程序的安装状态
+type programStatus struct {
+	Name          string   // 程序名
+	Category      string   // 程序类别，go、shell 或 unmanaged
+	Method        string   // 安装方式
+	Channel       string   // 发布渠道（只有基于 golang 的程序有）
+	LocalVersion  string   // 本地版本（shell 程序为脚本 Hash）
+	RemoteVersion string   // 远端版本（shell 程序为脚本 Hash）
+	Installed     bool     // 是否已安装
+	Outdated      bool     // 是否需要更新
+	Files         []string // 记账文件中记录的文件
+	RemoteErr     error    // 获取远端版本时的错误信息
+}
+
+// ListPrograms 列出已配置程序的安装状态
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - flags: 筛选条件的开关，为空时列出所有程序
+func ListPrograms(config *general.Config, flags map[string]bool) {
+	// 设置代理
+	general.SetVariable("http_proxy", config.Variable.HTTPProxy)
+	general.SetVariable("https_proxy", config.Variable.HTTPSProxy)
+	// 设置 API 认证和限流策略
+	general.SetupApiAuth(config)
+
+	// 检查是否设置了筛选条件
+	noFilter := !flags["installedFlag"] && !flags["missingFlag"] && !flags["outdatedFlag"]
+
+	// 收集所有程序的状态
+	statuses := make([]programStatus, 0)
+	// 管理程序本身按基于 golang 的程序检查
+	selfName := config.Program.Self.Name
+	if selfName != "" {
+		statuses = append(statuses, inspectProgram(config, "go", selfName))
+	}
+	goNames := slices.Clone(config.Program.Go.Names)
+	sort.Strings(goNames)
+	for _, program := range goNames {
+		if program == selfName {
+			continue
+		}
+		statuses = append(statuses, inspectProgram(config, "go", program))
+	}
+	shellNames := slices.Clone(config.Program.Shell.Names)
+	sort.Strings(shellNames)
+	for _, program := range shellNames {
+		statuses = append(statuses, inspectProgram(config, "shell", program))
+	}
+	// 有记账文件但不在配置中的程序
+	for _, program := range listPocketPrograms(config) {
+		if program == selfName || slices.Contains(goNames, program) || slices.Contains(shellNames, program) {
+			continue
+		}
+		statuses = append(statuses, inspectProgram(config, "unmanaged", program))
+	}
+
+	// 开始输出提示
+	color.Info.Tips("Installation path: %s", general.PrimaryText(config.Program.ProgramPath))
+	color.Info.Tips("Pocket path: %s", general.PrimaryText(config.Program.PocketPath))
+	color.Printf("%s\n", strings.Repeat(general.Separator1st, general.SeparatorBaseLength))
+
+	// 设置文本参数
+	textLength := 0 // 用于计算最后一行文本的长度，以便输出适当长度的分隔符
+
+	shownNum := 0 // 已输出程序数
+	for _, status := range statuses {
+		// 按筛选条件过滤
+		if !noFilter {
+			matched := (flags["installedFlag"] && status.Installed) ||
+				(flags["missingFlag"] && !status.Installed) ||
+				(flags["outdatedFlag"] && status.Outdated)
+			if !matched {
+				continue
+			}
+		}
+		shownNum++
+
+		// 状态符号和说明
+		statusFlag, statusText := general.LatestFlag, general.FgGreenText("latest")
+		switch {
+		case !status.Installed:
+			statusFlag, statusText = general.ErrorFlag, general.FgRedText("missing")
+		case status.RemoteErr != nil:
+			statusFlag, statusText = general.WarningFlag, general.FgYellowText("unknown")
+		case status.Outdated:
+			statusFlag, statusText = general.DownloadFlag, general.FgMagentaText("outdated")
+		}
+
+		// 版本信息
+		localVersion := status.LocalVersion
+		if localVersion == "" {
+			localVersion = "-"
+		}
+		remoteVersion := status.RemoteVersion
+		if remoteVersion == "" {
+			remoteVersion = "-"
+		}
+		text := color.Sprintf("%s %s %s %s %s %s %s\n", statusFlag, general.FgGreenText(status.Name), general.FgYellowText(localVersion), general.Indicator, general.NoteText(remoteVersion), statusText, general.SecondaryText("[", strings.Join(statusLabels(status), "/"), "]"))
+		color.Print(text)
+		textLength = general.RealLength(text) // 分隔符长度
+
+		// 获取远端版本失败的原因
+		if status.RemoteErr != nil {
+			fileName, lineNo := general.GetCallerInfo()
+			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), status.RemoteErr)
+		}
+
+		// 程序拥有的文件
+		for _, file := range status.Files {
+			color.Printf("%s %s\n", general.SecondaryText(general.Separator3st), file)
+		}
+
+		// 分隔符
+		general.PrintDelimiter(textLength)
+	}
+
+	if shownNum == 0 {
+		color.Warn.Tips("No program matches the filter")
+	}
+}
+
+// statusLabels 获取程序的类别、安装方式和发布渠道标签
+//
+// 参数：
+//   - status: 程序状态
+//
+// 返回：
+//   - 标签
+func statusLabels(status programStatus) []string {
+	labels := []string{status.Category, status.Method}
+	if status.Channel != "" {
+		labels = append(labels, status.Channel)
+	}
+	return labels
+}
+
+// inspectProgram 获取单个程序的安装状态
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - category: 程序类别，go、shell 或 unmanaged
+//   - program: 程序名
+//
+// 返回：
+//   - 程序状态
+func inspectProgram(config *general.Config, category, program string) programStatus {
+	status := programStatus{
+		Name:     program,
+		Category: category,
+	}
+
+	// 读取记账文件
+	pocketFile := filepath.Join(config.Program.PocketPath, program, config.Program.PocketFile) // 记账文件路径
+	var ledger *general.Ledger                                                                 // 记账信息
+	if general.FileExist(pocketFile) {
+		if pocketLedger, err := general.ReadLedger(pocketFile); err == nil {
+			ledger = pocketLedger
+			status.Files = ledger.Paths()
+		}
+	}
+
+	localProgram := filepath.Join(config.Program.ProgramPath, program) // 本地程序路径
+	switch category {
+	case "go":
+		status.Method = strings.ToLower(config.Program.Method)
+		status.Channel = config.Program.Go.ChannelOf(program)
+		status.LocalVersion, status.Installed = getGolangLocalVersion(localProgram)
+		status.RemoteVersion, status.RemoteErr = getGolangRemoteTag(config, program)
+		if status.Installed && status.RemoteErr == nil {
+			status.Outdated = golangNeedsUpdate(config, program, status.RemoteVersion, status.LocalVersion, true)
+		}
+	case "shell":
+		status.Method = "script"
+		localHash, installed := getShellLocalHash(localProgram)
+		status.Installed = installed
+		status.LocalVersion = shortHash(localHash)
+		remoteHash, err := getShellRemoteHash(config, program)
+		status.RemoteVersion, status.RemoteErr = shortHash(remoteHash), err
+		if status.Installed && status.RemoteErr == nil {
+			status.Outdated = remoteHash != localHash
+		}
+	default:
+		status.Method = "unknown"
+		if ledger != nil && ledger.Method != "" {
+			status.Method, status.LocalVersion = ledger.Method, ledger.Version
+		}
+		status.Installed = general.FileExist(localProgram) || len(status.Files) > 0
+	}
+
+	return status
+}
+
+// listPocketPrograms 列出记账目录中存在记账文件的程序
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//
+// 返回：
+//   - 程序名
+func listPocketPrograms(config *general.Config) []string {
+	programs := make([]string, 0)
+
+	entries, err := os.ReadDir(config.Program.PocketPath)
+	if err != nil {
+		return programs
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if general.FileExist(filepath.Join(config.Program.PocketPath, entry.Name(), config.Program.PocketFile)) {
+			programs = append(programs, entry.Name())
+		}
+	}
+	sort.Strings(programs)
+
+	return programs
+}
+
+// getGolangLocalVersion 获取基于 golang 的本地程序版本
+//
+// 参数：
+//   - localProgram: 本地程序路径
+//
+// 返回：
+//   - 本地程序版本
+//   - 本地程序是否存在
+func getGolangLocalVersion(localProgram string) (string, bool) {
+	programVersionArgs := []string{"version", "--only"} // 获取本地程序版本信息的参数
+	localVersion, _, commandErr := general.RunCommandToBuffer(localProgram, programVersionArgs)
+	if commandErr != nil {
+		return "", false
+	}
+	return localVersion, true
+}
+
+// getShellLocalHash 获取基于 shell 的本地脚本 Hash
+//
+// 参数：
+//   - localProgram: 本地脚本路径
+//
+// 返回：
+//   - 本地脚本 Hash
+//   - 本地脚本是否存在
+func getShellLocalHash(localProgram string) (string, bool) {
+	if !general.FileExist(localProgram) {
+		return "", false
+	}
+	programVersionArgs := []string{"hash-object", localProgram} // 获取本地程序版本信息的参数
+	localHash, _, commandErr := general.RunCommandToBuffer("git", programVersionArgs)
+	if commandErr != nil {
+		return "", true
+	}
+	return localHash, true
+}
+
+// getGolangRemoteTag 按配置的安装方式获取基于 golang 的程序的远端最新（或固定版本的） Tag
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - program: 程序名
+//
+// 返回：
+//   - 远端最新 Tag
+//   - 错误信息
+func getGolangRemoteTag(config *general.Config, program string) (string, error) {
+	switch strings.ToLower(config.Program.Method) {
+	case "release":
+		release, _, err := getGolangRelease(config, program, general.TryForges)
+		return release.Tag, err
+	case "source":
+		return getGolangSourceTag(config, program, general.TryForges)
+	default:
+		return "", fmt.Errorf("Unsupported installation method %s: only 'release' and 'source' are supported", config.Program.Method)
+	}
+}
+
+// getShellRemoteHash 获取基于 shell 的脚本的远端最新 Hash
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - program: 脚本名
+//
+// 返回：
+//   - 远端最新 Hash
+//   - 错误信息
+func getShellRemoteHash(config *general.Config, program string) (string, error) {
+	hash, _, err := getShellSourceHash(config, program, general.TryForges)
+	return hash, err
+}
+
+// shortHash 截取 Hash 的前 6 位用于显示
+//
+// 参数：
+//   - hash: 完整 Hash
+//
+// 返回：
+//   - 短 Hash
+func shortHash(hash string) string {
+	if len(hash) > 6 {
+		return hash[:6]
+	}
+	return hash
+}
@@ -0,0 +1,98 @@
+/*
+File: rollback.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-17 21:06:37
+
+Description: 子命令 'rollback' 的实现
+*/
+
+package cli
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/yhyj/manager/general"
+)
+
+// Rollback 将程序回滚到更新前保留的历史版本
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - program: 程序名
+//   - listOnly: 是否只列出可回滚的历史版本
+//   - version: 要回滚到的版本，为空时回滚到最近的历史版本
+func Rollback(config *general.Config, program string, listOnly bool, version string) {
+	// 只接受已配置或有记账文件的程序名，避免通过路径访问记账文件夹之外的文件
+	configured := program == config.Program.Self.Name || slices.Contains(config.Program.Go.Names, program) || slices.Contains(config.Program.Shell.Names, program)
+	if !filepath.IsLocal(program) || filepath.Base(program) != program || (!configured && !slices.Contains(listPocketPrograms(config), program)) {
+		color.Warn.Tips("Unknown program name: %s (not in the configured list and has no pocket file)", program)
+		return
+	}
+
+	pocketFile := filepath.Join(config.Program.PocketPath, program, config.Program.PocketFile) // 记账文件路径
+
+	// 获取历史版本
+	backups, err := general.ListBackupVersions(pocketFile)
+	if err != nil {
+		fileName, lineNo := general.GetCallerInfo()
+		color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+		return
+	}
+	if len(backups) == 0 {
+		color.Warn.Tips("Program \x1b[3m%s\x1b[0m has no previous version", general.FgCyanText(program))
+		return
+	}
+
+	// 只列出历史版本
+	if listOnly {
+		color.Info.Tips("Previous versions of \x1b[3m%s\x1b[0m (newest first)", general.FgCyanText(program))
+		color.Printf("%s\n", strings.Repeat(general.Separator1st, general.SeparatorBaseLength))
+		for _, backup := range backups {
+			color.Printf("%s %s\n", general.SecondaryText(general.Separator3st), general.FgYellowText(backup[1]))
+		}
+		return
+	}
+
+	// 选择要回滚到的历史版本
+	target := backups[0]
+	if version != "" {
+		found := false
+		for _, backup := range backups {
+			if backup[1] == version {
+				target, found = backup, true
+				break
+			}
+		}
+		if !found {
+			color.Warn.Tips("Version \x1b[3m%s\x1b[0m of \x1b[3m%s\x1b[0m is not retained", general.FgYellowText(version), general.FgCyanText(program))
+			return
+		}
+	}
+
+	// 开始回滚提示
+	color.Info.Tips("Roll back \x1b[3m%s\x1b[0m to %s", general.FgCyanText(program), general.FgYellowText(target[1]))
+	color.Printf("%s\n", strings.Repeat(general.Separator2st, general.SeparatorBaseLength))
+
+	// 设置文本参数
+	textLength := 0 // 用于计算最后一行文本的长度，以便输出适当长度的分隔符
+
+	// 恢复历史版本
+	if err := general.RestoreVersion(config.Program.PocketPath, program, pocketFile, target[0]); err != nil {
+		fileName, lineNo := general.GetCallerInfo()
+		text := color.Sprintf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+		color.Print(text)
+		textLength = general.RealLength(text) // 分隔符长度
+		general.PrintDelimiter(textLength)    // 分隔符
+		return
+	}
+
+	// 本次回滚结束分隔符
+	text := color.Sprintf("%s %s %s %s\n", general.SuccessFlag, general.FgGreenText(program), general.FgYellowText(target[1]), general.FgMagentaText("restored"))
+	color.Print(text)
+	textLength = general.RealLength(text) // 分隔符长度
+	general.PrintDelimiter(textLength)    // 分隔符
+}
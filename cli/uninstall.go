@@ -11,6 +11,7 @@ package cli
 
 import (
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -55,14 +56,15 @@ func UninstallSelf(config *general.Config) {
 		// 记账文件
 		pocketDir := filepath.Join(config.Program.PocketPath, program)    // 记账文件夹路径
 		pocketFile := filepath.Join(pocketDir, config.Program.PocketFile) // 记账文件路径
-		pocketLines := make([]string, 0)                                  // 记账文件内容
+		pocketLines := make([]string, 0)                                  // 记账文件中记录的文件
 		if general.FileExist(pocketFile) {                                // 读取记账文件内容
-			pocketLines, err = general.ReadFile(pocketFile)
+			ledger, err := general.ReadLedger(pocketFile)
 			if err != nil {
 				fileName, lineNo := general.GetCallerInfo()
 				color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 				return
 			}
+			pocketLines = ledger.Paths()
 		}
 		color.Printf("%s\n", strings.Repeat(general.Separator2st, len(question)))
 
@@ -114,7 +116,10 @@ func UninstallSelf(config *general.Config) {
 // 参数：
 //   - config: 解析 toml 配置文件得到的配置项
 //   - category: 要卸载的类别，支持 uninstall 子命令除 '--all' 和 '--self' 之外的所有 Flags
-func Uninstall(config *general.Config, category string) {
+//   - programs: 通过命令行参数指定的程序名，为空时由用户选择
+//   - allInstalled: 是否选择所有已安装的程序
+//   - assumeYes: 是否跳过卸载确认
+func Uninstall(config *general.Config, category string, programs []string, allInstalled, assumeYes bool) {
 	// 从配置读取指定类别的程序名
 	var programNames []string // 程序名切片
 	switch category {
@@ -147,7 +152,7 @@ func Uninstall(config *general.Config, category string) {
 	negatives.WriteString(color.Sprintf("%s Uninstall %s programs, %d/%d installed\n", general.InfoText("INFO:"), general.FgCyanText(category, "-based"), installedNum, totalNum))
 
 	// 让用户选择需要卸载的程序
-	selectedPrograms, err := general.MultipleSelectionFilter(installedPrograms, installedPrograms, negatives.String())
+	selectedPrograms, err := selectPrograms(installedPrograms, installedPrograms, nil, programs, allInstalled, negatives.String())
 	if err != nil {
 		fileName, lineNo := general.GetCallerInfo()
 		color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
@@ -168,13 +173,22 @@ func Uninstall(config *general.Config, category string) {
 
 	// 遍历所选程序/脚本名
 	for _, program := range selectedPrograms {
+		// 未安装的程序无需卸载
+		if !slices.Contains(installedPrograms, program) {
+			color.Warn.Tips("Program \x1b[3m%s\x1b[0m is not installed", general.FgCyanText(program))
+			continue
+		}
+
 		// 确认是否要卸载
 		question := color.Sprintf(general.UninstallTips, program)
-		answer, err := general.AreYouSure(general.QuestionText(question), false)
-		if err != nil {
-			fileName, lineNo := general.GetCallerInfo()
-			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
-			return
+		answer := true
+		if !assumeYes {
+			answer, err = general.AreYouSure(general.QuestionText(question), false)
+			if err != nil {
+				fileName, lineNo := general.GetCallerInfo()
+				color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+				return
+			}
 		}
 		switch answer {
 		case true:
@@ -182,14 +196,15 @@ func Uninstall(config *general.Config, category string) {
 			// 记账文件
 			pocketDir := filepath.Join(config.Program.PocketPath, program)    // 记账文件夹路径
 			pocketFile := filepath.Join(pocketDir, config.Program.PocketFile) // 记账文件路径
-			pocketLines := make([]string, 0)                                  // 记账文件内容
+			pocketLines := make([]string, 0)                                  // 记账文件中记录的文件
 			if general.FileExist(pocketFile) {                                // 读取记账文件内容
-				pocketLines, err = general.ReadFile(pocketFile)
+				ledger, err := general.ReadLedger(pocketFile)
 				if err != nil {
 					fileName, lineNo := general.GetCallerInfo()
 					color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
 					continue
 				}
+				pocketLines = ledger.Paths()
 			}
 
 			// 卸载程序
@@ -0,0 +1,262 @@
+/*
+File: verify.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-17 22:58:41
+
+Description: 子命令 'verify' 的实现
+*/
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/yhyj/manager/general"
+)
+
+// VerifyPrograms 根据记账文件校验已安装程序的完整性
+//
+//   - 没有校验基准（旧记账文件转换得到）的程序只警告，不算校验失败
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - programs: 通过命令行参数指定的程序名，为空时校验所有有记账文件的程序
+//   - fix: 是否重新安装校验失败或没有校验基准的程序
+//
+// 返回：
+//   - 仍然校验失败的程序数
+func VerifyPrograms(config *general.Config, programs []string, fix bool) int {
+	// 待校验的程序
+	targets := programs
+	if len(targets) == 0 {
+		targets = listPocketPrograms(config)
+	}
+
+	// 开始校验提示
+	color.Info.Tips("Pocket path: %s", general.PrimaryText(config.Program.PocketPath))
+	color.Printf("%s\n", strings.Repeat(general.Separator1st, general.SeparatorBaseLength))
+
+	if len(targets) == 0 {
+		color.Warn.Tips("No program has a pocket file")
+		return 0
+	}
+
+	// 校验程序
+	brokenPrograms := make([]string, 0) // 校验失败的程序
+	fixPrograms := make([]string, 0)    // 需要重新安装的程序，包括没有校验基准的程序
+	for _, program := range targets {
+		intact, baselined := reportProgramIntegrity(config, program)
+		if !intact {
+			brokenPrograms = append(brokenPrograms, program)
+		}
+		if !intact || !baselined {
+			fixPrograms = append(fixPrograms, program)
+		}
+	}
+
+	if !fix || len(fixPrograms) == 0 {
+		return len(brokenPrograms)
+	}
+
+	// 按类别重新安装校验失败或没有校验基准的程序
+	color.Info.Tips("Reinstall %d broken or unverified program(s)", len(fixPrograms))
+	color.Printf("%s\n", strings.Repeat(general.Separator1st, general.SeparatorBaseLength))
+	RecoverInstallations(config)
+	selfFix, goFix, shellFix := false, make([]string, 0), make([]string, 0)
+	unfixable := make([]string, 0) // 无法重新安装的程序
+	if config.Program.Go.Pins == nil {
+		config.Program.Go.Pins = make(map[string]string)
+	}
+	for _, program := range fixPrograms {
+		// 基于 golang 的程序固定为记账文件中记录的版本，避免修复时升级或切换发布渠道
+		if program == config.Program.Self.Name || slices.Contains(config.Program.Go.Names, program) {
+			if err := pinRecordedVersion(config, program); err != nil {
+				unfixable = append(unfixable, program)
+				color.Warn.Tips("Program \x1b[3m%s\x1b[0m can not be reinstalled: %s", general.FgCyanText(program), err)
+				continue
+			}
+		}
+		switch {
+		case program == config.Program.Self.Name:
+			selfFix = true
+		case slices.Contains(config.Program.Go.Names, program):
+			goFix = append(goFix, program)
+		case slices.Contains(config.Program.Shell.Names, program):
+			shellFix = append(shellFix, program)
+		default:
+			unfixable = append(unfixable, program)
+			color.Warn.Tips("Program \x1b[3m%s\x1b[0m is not in the configuration and can not be reinstalled", general.FgCyanText(program))
+		}
+	}
+	// 修复时不备份当前版本，避免被篡改或损坏的文件成为最新的历史版本并挤掉完好的历史版本
+	config.Program.RollbackKeep = 0
+	if selfFix {
+		InstallSelfProgram(config, true, false)
+	}
+	if len(goFix) > 0 {
+		InstallGolangBasedProgram(config, goFix, false, true, false)
+	}
+	if len(shellFix) > 0 {
+		InstallShellBasedProgram(config, shellFix, false, true)
+	}
+
+	// 重新校验已重新安装的程序，无法重新安装的程序保持原来的校验结果
+	color.Info.Tips("Verify reinstalled programs")
+	color.Printf("%s\n", strings.Repeat(general.Separator1st, general.SeparatorBaseLength))
+	remaining := 0
+	for _, program := range fixPrograms {
+		if slices.Contains(unfixable, program) {
+			if slices.Contains(brokenPrograms, program) {
+				remaining++
+			}
+			continue
+		}
+		if intact, _ := reportProgramIntegrity(config, program); !intact {
+			remaining++
+		}
+	}
+
+	return remaining
+}
+
+// pinRecordedVersion 将基于 golang 的程序固定为记账文件中记录的版本，并确认该版本仍可获取
+//
+//   - 旧记账文件没有记录版本，使用已安装程序报告的版本，仍无法获取时使用配置的版本
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - program: 程序名
+//
+// 返回：
+//   - 错误信息
+func pinRecordedVersion(config *general.Config, program string) error {
+	pocketFile := filepath.Join(config.Program.PocketPath, program, config.Program.PocketFile) // 记账文件路径
+	ledger, err := general.ReadLedger(pocketFile)
+	if err != nil {
+		return err
+	}
+
+	version, recorded := ledger.Version, ledger.Version != ""
+	if !recorded {
+		localProgram := filepath.Join(config.Program.ProgramPath, program) // 本地程序路径
+		if version, _, err = general.RunCommandToBuffer(localProgram, []string{"version", "--only"}); err != nil || version == "" {
+			return nil
+		}
+	}
+
+	previous := config.Program.Go.Pins[program]
+	config.Program.Go.Pins[program] = version
+	if _, err := getGolangRemoteTag(config, program); err != nil {
+		config.Program.Go.Pins[program] = previous
+		if !recorded {
+			return nil
+		}
+		return fmt.Errorf("Recorded version %s is no longer available: %s", version, err)
+	}
+
+	return nil
+}
+
+// reportProgramIntegrity 校验单个程序并输出结果
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - program: 程序名
+//
+// 返回：
+//   - 程序是否完好
+//   - 程序的所有文件是否都有校验基准
+func reportProgramIntegrity(config *general.Config, program string) (bool, bool) {
+	// 设置文本参数
+	textLength := 0 // 用于计算最后一行文本的长度，以便输出适当长度的分隔符
+
+	issues, warnings, err := verifyProgram(config, program)
+	if err != nil {
+		fileName, lineNo := general.GetCallerInfo()
+		text := color.Sprintf("%s %s %s %s\n", general.ErrorFlag, general.FgGreenText(program), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+		color.Print(text)
+		textLength = general.RealLength(text) // 分隔符长度
+		general.PrintDelimiter(textLength)    // 分隔符
+		return false, true
+	}
+
+	switch {
+	case len(issues) > 0:
+		text := color.Sprintf("%s %s %s\n", general.ErrorFlag, general.FgGreenText(program), general.FgRedText("broken"))
+		color.Print(text)
+		textLength = general.RealLength(text) // 分隔符长度
+	case len(warnings) > 0:
+		text := color.Sprintf("%s %s %s\n", general.WarningFlag, general.FgGreenText(program), general.FgYellowText("unverified"))
+		color.Print(text)
+		textLength = general.RealLength(text) // 分隔符长度
+	default:
+		text := color.Sprintf("%s %s %s\n", general.SuccessFlag, general.FgGreenText(program), general.FgMagentaText("intact"))
+		color.Print(text)
+		textLength = general.RealLength(text) // 分隔符长度
+	}
+	for _, issue := range append(issues, warnings...) {
+		color.Printf("%s %s\n", general.SecondaryText(general.Separator3st), issue)
+	}
+	general.PrintDelimiter(textLength) // 分隔符
+
+	return len(issues) == 0, len(warnings) == 0
+}
+
+// verifyProgram 根据记账文件校验单个程序拥有的文件
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+//   - program: 程序名
+//
+// 返回：
+//   - 校验失败的文件及原因
+//   - 无法校验的文件及原因
+//   - 错误信息
+func verifyProgram(config *general.Config, program string) ([]string, []string, error) {
+	issues := make([]string, 0)
+	warnings := make([]string, 0)
+
+	pocketFile := filepath.Join(config.Program.PocketPath, program, config.Program.PocketFile) // 记账文件路径
+	if !general.FileExist(pocketFile) {
+		return issues, warnings, fmt.Errorf("Pocket file %s does not exist", pocketFile)
+	}
+	ledger, err := general.ReadLedger(pocketFile)
+	if err != nil {
+		return issues, warnings, err
+	}
+
+	for _, file := range ledger.Files {
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			issues = append(issues, color.Sprintf("%s %s", general.FgRedText("missing"), file.Path))
+			continue
+		}
+		// 旧记账文件转换得到的文件没有校验基准
+		if !file.HasBaseline() {
+			warnings = append(warnings, color.Sprintf("%s %s: %s", general.FgYellowText("no baseline"), file.Path, general.NoteText("reinstall to record one")))
+		} else {
+			checksum, err := general.FileSHA256(file.Path)
+			if err != nil {
+				issues = append(issues, color.Sprintf("%s %s: %s", general.FgRedText("unreadable"), file.Path, err))
+				continue
+			}
+			if checksum != file.SHA256 {
+				issues = append(issues, color.Sprintf("%s %s", general.FgRedText("modified"), file.Path))
+			}
+		}
+		// Windows 不使用 Unix 权限
+		if file.Mode != "" && general.Platform != "windows" {
+			if mode := general.FormatFileMode(info.Mode()); mode != file.Mode {
+				issues = append(issues, color.Sprintf("%s %s %s %s %s", general.FgYellowText("permissions"), file.Path, general.FgYellowText(mode), general.Indicator, general.NoteText(file.Mode)))
+			}
+		}
+	}
+
+	return issues, warnings, nil
+}
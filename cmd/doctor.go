@@ -0,0 +1,59 @@
+/*
+File: doctor.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-17 23:18:02
+
+Description: 执行子命令 'doctor'
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/gookit/color"
+	"github.com/spf13/cobra"
+	"github.com/yhyj/manager/cli"
+	"github.com/yhyj/manager/general"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the environment and pocket files",
+	Long:  `Check the commands, paths and proxies that installation depends on, cross-check pocket files against the disk and print remedies for every problem found.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// 解析参数
+		configFile, _ := cmd.Flags().GetString("config")
+
+		// 读取配置文件
+		configTree, err := general.GetTomlConfig(configFile)
+		if err != nil {
+			fileName, lineNo := general.GetCallerInfo()
+			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+			return
+		}
+		// 获取配置项
+		config, err := general.LoadConfigToStruct(configTree)
+		if err != nil {
+			fileName, lineNo := general.GetCallerInfo()
+			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+			return
+		}
+
+		// 诊断，有错误级别的诊断项时以非零状态码退出
+		errorNum := cli.Doctor(config)
+
+		// 显示通知
+		general.Notification()
+		if errorNum > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	doctorCmd.Flags().BoolP("help", "h", false, "help for doctor command")
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -10,6 +10,8 @@ Description: 执行子命令 'install'
 package cmd
 
 import (
+	"os"
+
 	"github.com/gookit/color"
 	"github.com/spf13/cobra"
 	"github.com/yhyj/manager/cli"
@@ -18,7 +20,7 @@ import (
 
 // installCmd represents the install command
 var installCmd = &cobra.Command{
-	Use:   "install",
+	Use:   "install [flags] [program...]",
 	Short: "Install or update software and scripts (Use SSH key)",
 	Long:  `Install or update software and scripts from source/release using SSH key`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -28,6 +30,11 @@ var installCmd = &cobra.Command{
 		goFlag, _ := cmd.Flags().GetBool("go")
 		selfFlag, _ := cmd.Flags().GetBool("self")
 		shellFlag, _ := cmd.Flags().GetBool("shell")
+		checkFlag, _ := cmd.Flags().GetBool("check")
+		allInstalledFlag, _ := cmd.Flags().GetBool("all-installed")
+		refreshFlag, _ := cmd.Flags().GetBool("refresh")
+		forceFlag, _ := cmd.Flags().GetBool("force")
+		cleanFlag, _ := cmd.Flags().GetBool("clean")
 
 		// 读取配置文件
 		configTree, err := general.GetTomlConfig(configFile)
@@ -44,24 +51,87 @@ var installCmd = &cobra.Command{
 			return
 		}
 
+		// 设置 API 响应缓存
+		general.SetupApiCache(config.Program.CachePath, refreshFlag)
+
 		// 根据参数执行操作
-		if allFlag {
+		if allFlag || ((allInstalledFlag || len(args) > 0) && !goFlag && !shellFlag) {
 			goFlag, shellFlag = true, true
 		}
 
+		// 按类别拆分通过参数指定的程序名
+		goPrograms, shellPrograms, pins, err := cli.SplitProgramNames(config, args, goFlag, shellFlag)
+		if err != nil {
+			fileName, lineNo := general.GetCallerInfo()
+			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+			os.Exit(1)
+		}
+		// 通过参数固定的版本优先于配置文件中的版本
+		if config.Program.Go.Pins == nil {
+			config.Program.Go.Pins = make(map[string]string)
+		}
+		for program, version := range pins {
+			config.Program.Go.Pins[program] = version
+		}
+		if len(args) > 0 && !allInstalledFlag {
+			goFlag, shellFlag = len(goPrograms) > 0, len(shellPrograms) > 0
+		}
+
+		// 只检查是否需要安装/更新，有程序无法检查或有待安装/更新的程序时以不同的非零状态码退出
+		if checkFlag {
+			// 未指定类别和程序时检查所有类别
+			if !selfFlag && !goFlag && !shellFlag {
+				selfFlag, goFlag, shellFlag = true, true, true
+			}
+			pendingNum, failedNum := 0, 0
+			categories := []struct {
+				enabled  bool
+				category string
+				programs []string
+			}{
+				{selfFlag, "self", nil},
+				{goFlag, "go", goPrograms},
+				{shellFlag, "shell", shellPrograms},
+			}
+			for _, item := range categories {
+				if item.enabled {
+					pending, failed := cli.CheckProgramUpdates(config, item.category, item.programs)
+					pendingNum += pending
+					failedNum += failed
+				}
+			}
+			if pendingNum > 0 {
+				general.Notifier = append(general.Notifier, color.Sprintf("%d program(s) can be installed or updated", pendingNum))
+			}
+			if failedNum > 0 {
+				general.Notifier = append(general.Notifier, color.Sprintf("%d program(s) could not be checked", failedNum))
+			}
+			general.Notification()
+			switch {
+			case failedNum > 0:
+				os.Exit(general.CheckFailedExitCode)
+			case pendingNum > 0:
+				os.Exit(general.UpdatesPendingExitCode)
+			}
+			return
+		}
+
+		// 撤销上次运行时未完成的安装
+		cli.RecoverInstallations(config)
+
 		// 安装/更新管理程序本身
 		if selfFlag {
-			cli.InstallSelfProgram(config)
+			cli.InstallSelfProgram(config, forceFlag, cleanFlag)
 		}
 
 		// 安装/更新基于 golang 的程序
 		if goFlag {
-			cli.InstallGolangBasedProgram(config)
+			cli.InstallGolangBasedProgram(config, goPrograms, allInstalledFlag, forceFlag, cleanFlag)
 		}
 
 		// 安装/更新基于 shell 的程序
 		if shellFlag {
-			cli.InstallShellBasedProgram(config)
+			cli.InstallShellBasedProgram(config, shellPrograms, allInstalledFlag, forceFlag)
 		}
 
 		// 显示通知
@@ -74,6 +144,11 @@ func init() {
 	installCmd.Flags().Bool("all", false, "Install or update all software and scripts")
 	installCmd.Flags().Bool("go", false, "Install or update golang-based software")
 	installCmd.Flags().Bool("shell", false, "Install or update shell scripts")
+	installCmd.Flags().Bool("all-installed", false, "Update all installed software and scripts without prompting")
+	installCmd.Flags().Bool("check", false, "Only report what would be installed or updated, exit non-zero if anything is pending")
+	installCmd.Flags().Bool("refresh", false, "Ignore cached API responses and query the remote again")
+	installCmd.Flags().Bool("force", false, "Reinstall even if the remote version is not newer than the local version")
+	installCmd.Flags().Bool("clean", false, "Remove the cached source repositories and clone them again (source method)")
 
 	installCmd.Flags().BoolP("help", "h", false, "help for install command")
 	rootCmd.AddCommand(installCmd)
@@ -0,0 +1,67 @@
+/*
+File: list.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-17 19:28:40
+
+Description: 执行子命令 'list'
+*/
+
+package cmd
+
+import (
+	"github.com/gookit/color"
+	"github.com/spf13/cobra"
+	"github.com/yhyj/manager/cli"
+	"github.com/yhyj/manager/general"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed software and scripts",
+	Long:  `List configured software and scripts with their local version, latest remote version, installation method and owned files.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// 解析参数
+		configFile, _ := cmd.Flags().GetString("config")
+		allFlags := make(map[string]bool)
+		allFlags["installedFlag"], _ = cmd.Flags().GetBool("installed")
+		allFlags["missingFlag"], _ = cmd.Flags().GetBool("missing")
+		allFlags["outdatedFlag"], _ = cmd.Flags().GetBool("outdated")
+		refreshFlag, _ := cmd.Flags().GetBool("refresh")
+
+		// 读取配置文件
+		configTree, err := general.GetTomlConfig(configFile)
+		if err != nil {
+			fileName, lineNo := general.GetCallerInfo()
+			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+			return
+		}
+		// 获取配置项
+		config, err := general.LoadConfigToStruct(configTree)
+		if err != nil {
+			fileName, lineNo := general.GetCallerInfo()
+			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+			return
+		}
+
+		// 设置 API 响应缓存
+		general.SetupApiCache(config.Program.CachePath, refreshFlag)
+
+		// 列出程序
+		cli.ListPrograms(config, allFlags)
+
+		// 显示通知
+		general.Notification()
+	},
+}
+
+func init() {
+	listCmd.Flags().Bool("installed", false, "Only list installed software and scripts")
+	listCmd.Flags().Bool("missing", false, "Only list software and scripts that are not installed")
+	listCmd.Flags().Bool("outdated", false, "Only list software and scripts that have updates")
+	listCmd.Flags().Bool("refresh", false, "Ignore cached API responses and query the remote again")
+
+	listCmd.Flags().BoolP("help", "h", false, "help for list command")
+	rootCmd.AddCommand(listCmd)
+}
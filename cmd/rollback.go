@@ -0,0 +1,57 @@
+/*
+File: rollback.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-17 21:04:52
+
+Description: 执行子命令 'rollback'
+*/
+
+package cmd
+
+import (
+	"github.com/gookit/color"
+	"github.com/spf13/cobra"
+	"github.com/yhyj/manager/cli"
+	"github.com/yhyj/manager/general"
+)
+
+// rollbackCmd represents the rollback command
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback [flags] <program>",
+	Short: "Roll back software or scripts to a previous version",
+	Long:  `Restore the files and ledger of software or scripts kept before their last update.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// 解析参数
+		configFile, _ := cmd.Flags().GetString("config")
+		listFlag, _ := cmd.Flags().GetBool("list")
+		toFlag, _ := cmd.Flags().GetString("to")
+
+		// 读取配置文件
+		configTree, err := general.GetTomlConfig(configFile)
+		if err != nil {
+			fileName, lineNo := general.GetCallerInfo()
+			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+			return
+		}
+		// 获取配置项
+		config, err := general.LoadConfigToStruct(configTree)
+		if err != nil {
+			fileName, lineNo := general.GetCallerInfo()
+			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+			return
+		}
+
+		// 回滚程序
+		cli.Rollback(config, args[0], listFlag, toFlag)
+	},
+}
+
+func init() {
+	rollbackCmd.Flags().Bool("list", false, "List retained previous versions")
+	rollbackCmd.Flags().String("to", "", "Roll back to the specified retained version instead of the latest one")
+
+	rollbackCmd.Flags().BoolP("help", "h", false, "help for rollback command")
+	rootCmd.AddCommand(rollbackCmd)
+}
@@ -10,6 +10,8 @@ Description: 执行子命令 'uninstall'
 package cmd
 
 import (
+	"os"
+
 	"github.com/gookit/color"
 	"github.com/spf13/cobra"
 	"github.com/yhyj/manager/cli"
@@ -18,7 +20,7 @@ import (
 
 // uninstallCmd represents the uninstall command
 var uninstallCmd = &cobra.Command{
-	Use:   "uninstall",
+	Use:   "uninstall [flags] [program...]",
 	Short: "Uninstall software and scripts",
 	Long:  `Uninstall my software and scripts.`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -28,6 +30,8 @@ var uninstallCmd = &cobra.Command{
 		goFlag, _ := cmd.Flags().GetBool("go")
 		selfFlag, _ := cmd.Flags().GetBool("self")
 		shellFlag, _ := cmd.Flags().GetBool("shell")
+		allInstalledFlag, _ := cmd.Flags().GetBool("all-installed")
+		yesFlag, _ := cmd.Flags().GetBool("yes")
 
 		// 读取配置文件
 		configTree, err := general.GetTomlConfig(configFile)
@@ -45,10 +49,26 @@ var uninstallCmd = &cobra.Command{
 		}
 
 		// 根据参数执行操作
-		if allFlag {
+		if allFlag || ((allInstalledFlag || len(args) > 0) && !goFlag && !shellFlag) {
 			goFlag, shellFlag = true, true
 		}
 
+		// 按类别拆分通过参数指定的程序名
+		goPrograms, shellPrograms, pins, err := cli.SplitProgramNames(config, args, goFlag, shellFlag)
+		if err != nil {
+			fileName, lineNo := general.GetCallerInfo()
+			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+			os.Exit(1)
+		}
+		if len(pins) > 0 {
+			fileName, lineNo := general.GetCallerInfo()
+			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), "Version pinning is not supported when uninstalling")
+			os.Exit(1)
+		}
+		if len(args) > 0 && !allInstalledFlag {
+			goFlag, shellFlag = len(goPrograms) > 0, len(shellPrograms) > 0
+		}
+
 		// 卸载管理程序本身
 		if selfFlag {
 			cli.UninstallSelf(config)
@@ -56,12 +76,12 @@ var uninstallCmd = &cobra.Command{
 
 		// 卸载基于 golang 的程序
 		if goFlag {
-			cli.Uninstall(config, "go")
+			cli.Uninstall(config, "go", goPrograms, allInstalledFlag, yesFlag)
 		}
 
 		// 卸载基于 shell 的程序
 		if shellFlag {
-			cli.Uninstall(config, "shell")
+			cli.Uninstall(config, "shell", shellPrograms, allInstalledFlag, yesFlag)
 		}
 
 		// 显示通知
@@ -74,6 +94,8 @@ func init() {
 	uninstallCmd.Flags().Bool("all", false, "Uninstall all software and scripts")
 	uninstallCmd.Flags().Bool("go", false, "Uninstall golang-based software")
 	uninstallCmd.Flags().Bool("shell", false, "Uninstall shell scripts")
+	uninstallCmd.Flags().Bool("all-installed", false, "Uninstall all installed software and scripts without the selector")
+	uninstallCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation before uninstalling")
 
 	uninstallCmd.Flags().BoolP("help", "h", false, "help for uninstall command")
 	rootCmd.AddCommand(uninstallCmd)
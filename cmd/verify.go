@@ -0,0 +1,65 @@
+/*
+File: verify.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-17 22:56:13
+
+Description: 执行子命令 'verify'
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/gookit/color"
+	"github.com/spf13/cobra"
+	"github.com/yhyj/manager/cli"
+	"github.com/yhyj/manager/general"
+)
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify [flags] [program...]",
+	Short: "Verify installed software and scripts",
+	Long:  `Re-hash every file recorded in the pocket files and report files that are modified, missing or have wrong permissions.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// 解析参数
+		configFile, _ := cmd.Flags().GetString("config")
+		fixFlag, _ := cmd.Flags().GetBool("fix")
+
+		// 读取配置文件
+		configTree, err := general.GetTomlConfig(configFile)
+		if err != nil {
+			fileName, lineNo := general.GetCallerInfo()
+			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+			return
+		}
+		// 获取配置项
+		config, err := general.LoadConfigToStruct(configTree)
+		if err != nil {
+			fileName, lineNo := general.GetCallerInfo()
+			color.Printf("%s %s %s\n", general.DangerText(general.ErrorInfoFlag), general.SecondaryText("[", fileName, ":", lineNo+1, "]"), err)
+			return
+		}
+
+		// 设置 API 响应缓存
+		general.SetupApiCache(config.Program.CachePath, false)
+
+		// 校验程序，有校验失败的程序时以非零状态码退出
+		brokenNum := cli.VerifyPrograms(config, args, fixFlag)
+
+		// 显示通知
+		general.Notification()
+		if brokenNum > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	verifyCmd.Flags().Bool("fix", false, "Reinstall software and scripts that fail verification")
+
+	verifyCmd.Flags().BoolP("help", "h", false, "help for verify command")
+	rootCmd.AddCommand(verifyCmd)
+}
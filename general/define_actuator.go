@@ -62,6 +62,23 @@ func RunCommandToOS(command string, args []string) error {
 //   - Stderr 缓冲区内容
 //   - 错误信息
 func RunCommandToBuffer(command string, args []string) (string, string, error) {
+	return RunCommandToBufferWithEnv(command, args, nil)
+}
+
+// RunCommandToBufferWithEnv 使用指定的环境变量运行命令，将命令的 Stdout 和 Stderr 定向到字节缓冲区
+//
+//   - 命令的 Stdout 和 Stderr 末尾自带的换行符已去除
+//
+// 参数：
+//   - command: 命令
+//   - args: 命令参数（每个以空格分隔的参数作为切片的一个元素）
+//   - env: 环境变量（KEY=VALUE），为 nil 时使用当前进程的环境变量
+//
+// 返回：
+//   - Stdout 缓冲区内容
+//   - Stderr 缓冲区内容
+//   - 错误信息
+func RunCommandToBufferWithEnv(command string, args []string, env []string) (string, string, error) {
 	// 检查命令是否存在，添加了对 `sudo` 命令的规避，`sudo`命令应独立检测
 	if command == "sudo" {
 		command = args[0]
@@ -73,6 +90,7 @@ func RunCommandToBuffer(command string, args []string) (string, string, error) {
 
 	// 定义命令
 	cmd := exec.Command(command, args...)
+	cmd.Env = env
 
 	// 创建字节缓冲区
 	var stdout bytes.Buffer
@@ -0,0 +1,231 @@
+/*
+File: define_api.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-17 23:41:27
+
+Description: API 认证和限流处理
+*/
+
+package general
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gookit/color"
+)
+
+// API 限流策略
+const (
+	RateLimitWarn     = "warn"     // 剩余请求数不足时警告，耗尽后请求失败
+	RateLimitWait     = "wait"     // 耗尽后等待限流重置再请求
+	RateLimitFallback = "fallback" // 耗尽后在重置前不再请求该主机，直接失败以便切换到备用仓库
+)
+
+var (
+	apiAuthorizations = make(map[string]string)       // API 主机对应的 Authorization 请求头
+	apiRateLimits     = make(map[string]apiRateLimit) // API 主机的限流状态
+	rateLimitPolicy   = RateLimitFallback             // API 限流策略
+	rateLimitLowMark  = 10                            // 剩余请求数低于该值时警告
+	rateLimitMaxWait  = time.Hour                     // 'wait' 策略的最长等待时间
+	rateLimitRetries  = 1                             // 'wait' 策略等待后重试的次数，之后仍被限流则失败
+)
+
+// apiRateLimit 单个 API 主机的限流状态
+type apiRateLimit struct {
+	Limit     int       // 限流周期内允许的请求数
+	Remaining int       // 剩余请求数
+	Reset     time.Time // 限流重置时间
+	Warned    bool      // 是否已经警告过
+}
+
+// RateLimitError API 请求数耗尽
+type RateLimitError struct {
+	Host  string    // API 主机
+	Reset time.Time // 限流重置时间
+}
+
+// Error 实现 error 接口
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("API rate limit of %s exceeded, resets at %s; set a token to raise the limit", e.Host, e.Reset.Format("15:04:05"))
+}
+
+// SetupApiAuth 根据配置和环境变量设置各 API 主机的 Token 和限流策略，环境变量优先于配置文件
+//
+//   - GitHub Token：环境变量 GITHUB_TOKEN 或 GH_TOKEN，配置项 github_token
+//   - Gitea/Forgejo Token：环境变量 GITEA_TOKEN，配置项 gitea_token
+//   - GitLab Token：环境变量 GITLAB_TOKEN，配置项 gitlab_token
+//
+// 参数：
+//   - config: 解析 toml 配置文件得到的配置项
+func SetupApiAuth(config *Config) {
+	githubToken := firstNonEmpty(GetVariable("GITHUB_TOKEN"), GetVariable("GH_TOKEN"), config.Variable.GithubToken)
+	giteaToken := firstNonEmpty(GetVariable("GITEA_TOKEN"), config.Variable.GiteaToken)
+	gitlabToken := firstNonEmpty(GetVariable("GITLAB_TOKEN"), config.Variable.GitlabToken)
+
+	// GitHub 和 GitLab 使用 Bearer 认证，Gitea/Forgejo 使用 token 认证
+	for _, mirror := range config.Program.Mirrors {
+		host, err := GetUrlHost(mirror.Api)
+		if err != nil || host == "" {
+			continue
+		}
+		switch kind := strings.ToLower(mirror.Kind); {
+		case kind == MirrorKindGithub && githubToken != "":
+			apiAuthorizations[host] = color.Sprintf("Bearer %s", githubToken)
+		case (kind == MirrorKindGitea || kind == MirrorKindForgejo) && giteaToken != "":
+			apiAuthorizations[host] = color.Sprintf("token %s", giteaToken)
+		case kind == MirrorKindGitlab && gitlabToken != "":
+			apiAuthorizations[host] = color.Sprintf("Bearer %s", gitlabToken)
+		}
+	}
+
+	switch strings.ToLower(config.Variable.RateLimit) {
+	case RateLimitWarn:
+		rateLimitPolicy = RateLimitWarn
+	case RateLimitWait:
+		rateLimitPolicy = RateLimitWait
+	default:
+		rateLimitPolicy = RateLimitFallback
+	}
+}
+
+// authorizeRequest 为请求设置对应主机的 Authorization 请求头
+//
+// 参数：
+//   - req: HTTP 请求
+func authorizeRequest(req *http.Request) {
+	if authorization, ok := apiAuthorizations[req.URL.Host]; ok {
+		req.Header.Set("Authorization", authorization)
+	}
+}
+
+// checkRateLimit 在请求前检查主机的限流状态，按限流策略等待或直接失败
+//
+// 参数：
+//   - host: API 主机
+//
+// 返回：
+//   - 错误信息
+func checkRateLimit(host string) error {
+	state, ok := apiRateLimits[host]
+	if !ok || state.Remaining > 0 || time.Now().After(state.Reset) {
+		return nil
+	}
+
+	switch rateLimitPolicy {
+	case RateLimitWait:
+		return waitRateLimit(host, state.Reset)
+	case RateLimitFallback:
+		return &RateLimitError{Host: host, Reset: state.Reset}
+	default:
+		return nil
+	}
+}
+
+// waitRateLimit 等待主机的限流重置
+//
+// 参数：
+//   - host: API 主机
+//   - reset: 限流重置时间
+//
+// 返回：
+//   - 错误信息，等待时间超过上限时返回
+func waitRateLimit(host string, reset time.Time) error {
+	wait := time.Until(reset) + time.Second
+	if wait > rateLimitMaxWait {
+		return &RateLimitError{Host: host, Reset: reset}
+	}
+	color.Warn.Tips("API rate limit of %s exceeded, waiting %s until it resets", host, wait.Round(time.Second))
+	time.Sleep(wait)
+	delete(apiRateLimits, host)
+	return nil
+}
+
+// recordRateLimit 根据响应头 X-RateLimit-*（GitLab 为 RateLimit-*）记录主机的限流状态，剩余请求数不足时警告
+//
+// 参数：
+//   - host: API 主机
+//   - header: 响应头
+//
+// 返回：
+//   - 主机的请求数是否已耗尽
+func recordRateLimit(host string, header http.Header) bool {
+	prefix := "X-RateLimit-"
+	if header.Get(prefix+"Remaining") == "" {
+		prefix = "RateLimit-"
+	}
+	remaining, err := strconv.Atoi(header.Get(prefix + "Remaining"))
+	if err != nil { // 该主机不提供限流信息
+		return false
+	}
+	limit, _ := strconv.Atoi(header.Get(prefix + "Limit"))
+	resetUnix, _ := strconv.ParseInt(header.Get(prefix+"Reset"), 10, 64)
+
+	state := apiRateLimits[host]
+	state.Limit, state.Remaining, state.Reset = limit, remaining, time.Unix(resetUnix, 0)
+	if remaining < rateLimitLowMark && !state.Warned {
+		color.Warn.Tips("Only %d of %d API requests left for %s until %s", remaining, limit, host, state.Reset.Format("15:04:05"))
+		state.Warned = true
+	}
+	apiRateLimits[host] = state
+
+	return remaining == 0
+}
+
+// nextPageUrl 根据响应头 Link（rel="next"，GitHub、Gitea 和 GitLab）或 X-Next-Page（GitLab）获取下一页的地址
+//
+// 参数：
+//   - current: 当前页的地址
+//   - header: 响应头
+//
+// 返回：
+//   - 下一页的地址，没有下一页时为空
+func nextPageUrl(current *url.URL, header http.Header) string {
+	// Link: <https://api.github.com/...&page=2>; rel="next", <https://api.github.com/...&page=5>; rel="last"
+	for _, link := range strings.Split(strings.Join(header.Values("Link"), ","), ",") {
+		target, params, found := strings.Cut(link, ";")
+		if !found {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			if strings.ReplaceAll(strings.TrimSpace(param), " ", "") == `rel="next"` {
+				next, err := current.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+				if err != nil {
+					return ""
+				}
+				return next.String()
+			}
+		}
+	}
+
+	if page := header.Get("X-Next-Page"); page != "" {
+		next := *current
+		query := next.Query()
+		query.Set("page", page)
+		next.RawQuery = query.Encode()
+		return next.String()
+	}
+
+	return ""
+}
+
+// firstNonEmpty 返回第一个非空字符串
+//
+// 参数：
+//   - values: 候选字符串
+//
+// 返回：
+//   - 第一个非空字符串，都为空时返回空字符串
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -13,16 +13,134 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"bufio"
+	"bytes"
+	"compress/bzip2"
 	"compress/gzip"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/gookit/color"
 )
 
+// MaxUncompressedSize 解压后允许的最大总大小（字节），防止压缩炸弹耗尽磁盘空间
+var MaxUncompressedSize int64 = 2 << 30
+
+// archiveFileTypes 支持的压缩包后缀，按优先级排列
+var archiveFileTypes = []string{"tar.gz", "tgz", "tar.xz", "txz", "tar.zst", "tzst", "tar.bz2", "tbz2", "zip"}
+
+// ArchiveFileNames 获取 Release 文件可能的文件名，按优先级排列
+//
+//   - 依次是各种后缀的压缩包（Windows 平台优先 zip，其他平台优先 tar.gz）和无需解压的可执行文件
+//
+// 参数：
+//   - nameWithoutType: 不带后缀的文件名
+//
+// 返回：
+//   - 可能的文件名
+func ArchiveFileNames(nameWithoutType string) []string {
+	fileNames := make([]string, 0, len(archiveFileTypes)+2)
+	if Platform == "windows" {
+		fileNames = append(fileNames, color.Sprintf("%s.zip", nameWithoutType))
+	}
+	for _, fileType := range archiveFileTypes {
+		fileName := color.Sprintf("%s.%s", nameWithoutType, fileType)
+		if len(fileNames) == 0 || fileNames[0] != fileName {
+			fileNames = append(fileNames, fileName)
+		}
+	}
+	if Platform == "windows" {
+		fileNames = append(fileNames, color.Sprintf("%s.exe", nameWithoutType))
+	}
+	return append(fileNames, nameWithoutType)
+}
+
+// TrimArchiveType 去掉压缩包文件名的后缀，得到解压后的文件夹名
+//
+// 参数：
+//   - fileName: 压缩包文件名
+//
+// 返回：
+//   - 不带后缀的文件名
+func TrimArchiveType(fileName string) string {
+	fileName = filepath.Base(fileName)
+	for _, fileType := range archiveFileTypes {
+		if strings.HasSuffix(fileName, "."+fileType) {
+			return strings.TrimSuffix(fileName, "."+fileType)
+		}
+	}
+	return strings.TrimSuffix(fileName, filepath.Ext(fileName))
+}
+
+// hasArchiveType 检查文件名是否带有支持的压缩包后缀或 .exe 后缀
+//
+// 参数：
+//   - fileName: 文件名
+//
+// 返回：
+//   - 是否带有后缀
+func hasArchiveType(fileName string) bool {
+	for _, fileType := range append(archiveFileTypes, "exe") {
+		if strings.HasSuffix(fileName, "."+fileType) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExecutableFile 通过魔数检测文件是否为无需解压的可执行文件（ELF、Mach-O 或 PE）
+//
+// 参数：
+//   - filePath: 待检测文件
+//
+// 返回：
+//   - 是否为可执行文件
+func IsExecutableFile(filePath string) bool {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(file, magic); err != nil {
+		return false
+	}
+	return isExecutableMagic(magic)
+}
+
+// isExecutableMagic 检测文件头部的魔数是否属于可执行文件
+//
+// 参数：
+//   - magic: 文件头部
+//
+// 返回：
+//   - 是否为可执行文件
+func isExecutableMagic(magic []byte) bool {
+	executableMagics := [][]byte{
+		[]byte("\x7FELF"),        // ELF
+		[]byte("MZ"),             // PE
+		{0xFE, 0xED, 0xFA, 0xCE}, // Mach-O 32 位
+		{0xFE, 0xED, 0xFA, 0xCF}, // Mach-O 64 位
+		{0xCE, 0xFA, 0xED, 0xFE}, // Mach-O 32 位（小端）
+		{0xCF, 0xFA, 0xED, 0xFE}, // Mach-O 64 位（小端）
+		{0xCA, 0xFE, 0xBA, 0xBE}, // Mach-O 通用二进制
+	}
+	for _, executableMagic := range executableMagics {
+		if bytes.HasPrefix(magic, executableMagic) {
+			return true
+		}
+	}
+	return false
+}
+
 // UnzipFile 检测压缩文件类型，执行相应的解压函数
 //
+//   - 支持 zip、tar.gz、tar.bz2、tar.xz 和 tar.zst，其中 tar.xz 和 tar.zst 需要系统中有 xz 和 zstd 命令
+//
 // 参数：
 //   - filePath: 待解压文件
 //   - outputDir: 解压文件的存储目录
@@ -37,9 +155,6 @@ func UnzipFile(filePath, outputDir string) error {
 	}
 	defer file.Close()
 
-	// 接收解压缩函数并执行
-	var unzipFunc func(string, string) error
-
 	// 通过读取文件头部信息和魔数对比来检测文件类型
 	bufferedReader := bufio.NewReader(file)
 	fileType, err := bufferedReader.Peek(10)
@@ -48,15 +163,72 @@ func UnzipFile(filePath, outputDir string) error {
 	}
 	// 根据文件类型选择相应的解压缩函数
 	switch {
-	case strings.HasPrefix(string(fileType), "PK\x03\x04"): // zip 文件的魔数
-		unzipFunc = unzipZip
-	case strings.HasPrefix(string(fileType), "\x1F\x8B"): // tar.gz 文件的魔数
-		unzipFunc = unzipTarGz
+	case bytes.HasPrefix(fileType, []byte("PK\x03\x04")): // zip 文件的魔数
+		return unzipZip(filePath, outputDir)
+	case bytes.HasPrefix(fileType, []byte("\x1F\x8B")): // tar.gz 文件的魔数
+		return unzipTar(filePath, outputDir, func(reader io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(reader)
+		})
+	case bytes.HasPrefix(fileType, []byte("BZh")): // tar.bz2 文件的魔数
+		return unzipTar(filePath, outputDir, func(reader io.Reader) (io.ReadCloser, error) {
+			return io.NopCloser(bzip2.NewReader(reader)), nil
+		})
+	case bytes.HasPrefix(fileType, []byte("\xFD7zXZ\x00")): // tar.xz 文件的魔数
+		return unzipTar(filePath, outputDir, commandDecompressor("xz"))
+	case bytes.HasPrefix(fileType, []byte("\x28\xB5\x2F\xFD")): // tar.zst 文件的魔数
+		return unzipTar(filePath, outputDir, commandDecompressor("zstd"))
+	case isExecutableMagic(fileType):
+		return fmt.Errorf("%s is an executable file and needs no extraction", filepath.Base(filePath))
 	default:
 		return fmt.Errorf("Unsupported compressed file type")
 	}
+}
+
+// commandReader 外部解压命令的输出，关闭时等待命令退出
+type commandReader struct {
+	io.ReadCloser           // 命令的标准输出
+	cmd           *exec.Cmd // 解压命令
+	stderr        *bytes.Buffer
+}
 
-	return unzipFunc(filePath, outputDir)
+// Close 关闭命令的输出并等待命令退出
+//
+// 返回：
+//   - 错误信息，命令执行失败时包含其标准错误输出
+func (r *commandReader) Close() error {
+	r.ReadCloser.Close()
+	if err := r.cmd.Wait(); err != nil {
+		return fmt.Errorf("%s: %s %s", r.cmd.Path, err, strings.TrimSpace(r.stderr.String()))
+	}
+	return nil
+}
+
+// commandDecompressor 使用外部命令（xz、zstd）解压数据流
+//
+// 参数：
+//   - command: 解压命令，需要支持 -dc 参数
+//
+// 返回：
+//   - 解压函数
+func commandDecompressor(command string) func(io.Reader) (io.ReadCloser, error) {
+	return func(reader io.Reader) (io.ReadCloser, error) {
+		path, err := exec.LookPath(command)
+		if err != nil {
+			return nil, fmt.Errorf("Command '%s' is required to decompress this archive: %s", command, err)
+		}
+		cmd := exec.Command(path, "-dc")
+		cmd.Stdin = reader
+		stderr := &bytes.Buffer{}
+		cmd.Stderr = stderr
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		return &commandReader{ReadCloser: stdout, cmd: cmd, stderr: stderr}, nil
+	}
 }
 
 // unzipZip 解压 zip 格式压缩包
@@ -74,12 +246,13 @@ func unzipZip(filePath, outputDir string) error {
 	}
 	defer reader.Close()
 
-	fileNameWithExt := filepath.Base(filePath)                         // 带后缀的文件名
-	fileExt := filepath.Ext(fileNameWithExt)                           // 文件后缀
-	fileNameWithoutExt := strings.TrimSuffix(fileNameWithExt, fileExt) // 不带后缀的文件名
-	outputTo := filepath.Join(outputDir, fileNameWithoutExt)
+	outputTo := filepath.Join(outputDir, TrimArchiveType(filePath))
+	extractor, err := newArchiveExtractor(outputTo)
+	if err != nil {
+		return err
+	}
 	for _, file := range reader.File {
-		if err := extractZipFile(file, outputTo); err != nil {
+		if err := extractZipFile(file, extractor); err != nil {
 			return err
 		}
 	}
@@ -91,93 +264,67 @@ func unzipZip(filePath, outputDir string) error {
 //
 // 参数：
 //   - file: 待解压文件
-//   - outputDir: 解压文件的存储目录
+//   - extractor: 解压器
 //
 // 返回：
 //   - 错误信息
-func extractZipFile(file *zip.File, outputDir string) error {
-	// 创建目标目录
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return err
-	}
-
-	// 拼接 file 解压后的路径
-	path := filepath.Join(outputDir, file.Name)
-
+func extractZipFile(file *zip.File, extractor *archiveExtractor) error {
 	// 如果 file 是文件夹
 	if file.FileInfo().IsDir() {
-		// 在输出目录创建该文件夹后返回
-		if err := os.MkdirAll(path, 0755); err != nil {
-			return err
-		}
-		return nil
+		return extractor.mkdir(file.Name)
 	}
 
-	// 如果 file 是普通文件
-	// 1. 读取该文件内容
+	// 如果 file 是普通文件或符号链接，读取其内容
 	reader, err := file.Open()
 	if err != nil {
 		return err
 	}
 	defer reader.Close()
 
-	// 2. 在输出目录创建该文件
-	writer, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	defer writer.Close()
-
-	// 3. 拷贝 file 的内容到创建的文件
-	if _, err := io.Copy(writer, reader); err != nil {
-		return err
-	}
-
-	// 4. 从 file 恢复文件权限
-	if err := os.Chmod(path, file.Mode()); err != nil {
-		return err
+	// 如果 file 是符号链接，其内容为链接目标
+	if file.Mode()&os.ModeSymlink != 0 {
+		target, err := io.ReadAll(io.LimitReader(reader, 4096))
+		if err != nil {
+			return err
+		}
+		return extractor.symlink(file.Name, string(target))
 	}
 
-	return nil
+	// 如果 file 是普通文件
+	return extractor.writeFile(file.Name, reader, file.Mode())
 }
 
-// unzipTarGz 解压 tar.gz 格式压缩包
+// unzipTar 解压 tar 格式压缩包
 //
 // 参数：
 //   - filePath: 待解压文件
 //   - outputDir: 解压文件的存储目录
+//   - decompress: 将压缩数据流转换为 tar 数据流的解压函数
 //
 // 返回：
 //   - 错误信息
-func unzipTarGz(filePath, outputDir string) error {
+func unzipTar(filePath, outputDir string, decompress func(io.Reader) (io.ReadCloser, error)) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	// 使用 gzip 读取文件
-	gzipReader, err := gzip.NewReader(file)
+	// 解压数据流
+	decompressedReader, err := decompress(file)
 	if err != nil {
 		return err
 	}
-	defer gzipReader.Close()
+	defer decompressedReader.Close()
 
-	// 使用 tar 读取 gzip 流
-	tarReader := tar.NewReader(gzipReader)
+	// 使用 tar 读取解压后的数据流
+	tarReader := tar.NewReader(decompressedReader)
 
-	fileName := func() string {
-		switch {
-		case strings.HasSuffix(filePath, ".tar.gz"):
-			return strings.TrimSuffix(filepath.Base(filePath), ".tar.gz")
-		default:
-			fileNameWithExt := filepath.Base(filePath)                         // 带后缀的文件名
-			fileExt := filepath.Ext(fileNameWithExt)                           // 文件后缀
-			fileNameWithoutExt := strings.TrimSuffix(fileNameWithExt, fileExt) // 不带后缀的文件名
-			return fileNameWithoutExt
-		}
-	}()
-	outputTo := filepath.Join(outputDir, fileName)
+	outputTo := filepath.Join(outputDir, TrimArchiveType(filePath))
+	extractor, err := newArchiveExtractor(outputTo)
+	if err != nil {
+		return err
+	}
 	for {
 		header, err := tarReader.Next()
 		if err == io.EOF {
@@ -187,12 +334,16 @@ func unzipTarGz(filePath, outputDir string) error {
 			return err
 		}
 
-		if err = extractTarFile(header, tarReader, outputTo); err != nil {
+		if err = extractTarFile(header, tarReader, extractor); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	// 读完 tar 结束标记之后的填充数据，使外部解压命令正常退出
+	if _, err := io.Copy(io.Discard, decompressedReader); err != nil {
+		return err
+	}
+	return decompressedReader.Close()
 }
 
 // extractTarFile 解压 tar 格式压缩包中的单个文件
@@ -200,48 +351,209 @@ func unzipTarGz(filePath, outputDir string) error {
 // 参数：
 //   - header: 待解压文件头信息
 //   - reader: 待解压文件内容读取器
-//   - outputDir: 解压文件的存储目录
+//   - extractor: 解压器
 //
 // 返回：
 //   - 错误信息
-func extractTarFile(header *tar.Header, reader io.Reader, outputDir string) error {
-	// 创建目标目录
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return err
-	}
-
-	// 拼接 header.Name 解压后的路径
-	path := filepath.Join(outputDir, header.Name)
-
+func extractTarFile(header *tar.Header, reader io.Reader, extractor *archiveExtractor) error {
 	switch header.Typeflag {
 	case tar.TypeDir: // 如果 header.Name 是文件夹
-		// 在输出目录创建该文件夹
-		if err := os.MkdirAll(path, 0755); err != nil {
-			return err
-		}
+		return extractor.mkdir(header.Name)
 	case tar.TypeReg: // 如果 header.Name 是普通文件
-		// 因为 tar 包中 header.Name 是最终文件（即文件或空文件夹），所以需要在输出目录创建其父文件夹
-		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-			return err
-		}
+		return extractor.writeFile(header.Name, reader, os.FileMode(header.Mode).Perm())
+	case tar.TypeSymlink: // 如果 header.Name 是符号链接
+		return extractor.symlink(header.Name, header.Linkname)
+	case tar.TypeLink: // 如果 header.Name 是硬链接
+		return extractor.hardlink(header.Name, header.Linkname)
+	}
 
-		// 1. 在输出目录创建该文件
-		writer, err := os.Create(path)
+	return nil
+}
+
+// archiveExtractor 将压缩包中的文件安全地解压到目标目录
+//
+//   - 拒绝路径逃逸出目标目录的文件和指向目标目录之外的链接
+//   - 拒绝经由已解压的符号链接写入文件
+//   - 解压后的总大小超过 MaxUncompressedSize 时中止
+type archiveExtractor struct {
+	root    string // 目标目录
+	written int64  // 已解压的总大小
+}
+
+// newArchiveExtractor 创建解压器，同时创建目标目录
+//
+// 参数：
+//   - root: 目标目录
+//
+// 返回：
+//   - 解压器
+//   - 错误信息
+func newArchiveExtractor(root string) (*archiveExtractor, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+	return &archiveExtractor{root: filepath.Clean(root)}, nil
+}
+
+// path 获取压缩包中的文件解压后的路径
+//
+// 参数：
+//   - name: 压缩包中的文件名
+//
+// 返回：
+//   - 解压后的路径
+//   - 错误信息，文件名逃逸出目标目录或其父目录是符号链接时不为 nil
+func (e *archiveExtractor) path(name string) (string, error) {
+	relPath := filepath.Clean(filepath.FromSlash(name))
+	if !filepath.IsLocal(relPath) {
+		return "", fmt.Errorf("Illegal file path in archive: %s", name)
+	}
+
+	// 父目录不能是符号链接，否则可以经由符号链接写入目标目录之外
+	parts := strings.Split(relPath, string(filepath.Separator))
+	current := e.root
+	for _, part := range parts[:len(parts)-1] {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			break
+		}
 		if err != nil {
-			return err
+			return "", err
 		}
-		defer writer.Close()
-
-		// 2. 拷贝 header.Name 的内容到创建的文件
-		if _, err := io.Copy(writer, reader); err != nil {
-			return err
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("Illegal file path in archive: %s (parent directory is a symlink)", name)
 		}
+	}
 
-		// 3. 从 header.Name 恢复文件权限
-		if err = os.Chmod(path, os.FileMode(header.Mode)); err != nil {
-			return err
+	return filepath.Join(e.root, relPath), nil
+}
+
+// mkdir 创建文件夹
+//
+// 参数：
+//   - name: 压缩包中的文件夹名
+//
+// 返回：
+//   - 错误信息
+func (e *archiveExtractor) mkdir(name string) error {
+	path, err := e.path(name)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("Illegal file path in archive: %s (is a symlink)", name)
+	}
+	return os.MkdirAll(path, 0755)
+}
+
+// prepare 为即将创建的文件创建父文件夹，并删除同名的文件或链接
+//
+// 参数：
+//   - name: 压缩包中的文件名
+//
+// 返回：
+//   - 解压后的路径
+//   - 错误信息
+func (e *archiveExtractor) prepare(name string) (string, error) {
+	path, err := e.path(name)
+	if err != nil {
+		return "", err
+	}
+	// 压缩包中的文件名是最终文件（即文件或空文件夹），所以需要在输出目录创建其父文件夹
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	// 删除同名的文件或链接，避免经由链接写入其他文件
+	if info, err := os.Lstat(path); err == nil {
+		if info.IsDir() {
+			return "", fmt.Errorf("Illegal file path in archive: %s (is a directory)", name)
+		}
+		if err := os.Remove(path); err != nil {
+			return "", err
 		}
 	}
+	return path, nil
+}
 
-	return nil
+// writeFile 创建普通文件
+//
+// 参数：
+//   - name: 压缩包中的文件名
+//   - reader: 文件内容读取器
+//   - mode: 文件权限
+//
+// 返回：
+//   - 错误信息
+func (e *archiveExtractor) writeFile(name string, reader io.Reader, mode os.FileMode) error {
+	path, err := e.prepare(name)
+	if err != nil {
+		return err
+	}
+
+	// 1. 在输出目录创建该文件
+	writer, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+
+	// 2. 拷贝文件内容到创建的文件，最多拷贝到剩余额度多 1 字节以检测超限
+	remaining := MaxUncompressedSize - e.written
+	written, err := io.CopyN(writer, reader, remaining+1)
+	e.written += written
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if e.written > MaxUncompressedSize {
+		return fmt.Errorf("Archive exceeds the maximum uncompressed size of %d bytes", MaxUncompressedSize)
+	}
+
+	// 3. 恢复文件权限
+	return os.Chmod(path, mode.Perm())
+}
+
+// symlink 创建符号链接，链接目标必须位于目标目录之内
+//
+// 参数：
+//   - name: 压缩包中的链接名
+//   - target: 链接目标
+//
+// 返回：
+//   - 错误信息
+func (e *archiveExtractor) symlink(name, target string) error {
+	relTarget := filepath.FromSlash(target)
+	if filepath.IsAbs(relTarget) || !filepath.IsLocal(filepath.Join(filepath.Dir(filepath.FromSlash(name)), relTarget)) {
+		return fmt.Errorf("Illegal symlink in archive: %s -> %s (points outside the destination)", name, target)
+	}
+
+	path, err := e.prepare(name)
+	if err != nil {
+		return err
+	}
+	return os.Symlink(relTarget, path)
+}
+
+// hardlink 创建硬链接，链接目标必须是目标目录之内已解压的普通文件
+//
+// 参数：
+//   - name: 压缩包中的链接名
+//   - target: 链接目标（压缩包中的文件名）
+//
+// 返回：
+//   - 错误信息
+func (e *archiveExtractor) hardlink(name, target string) error {
+	targetPath, err := e.path(target)
+	if err != nil {
+		return fmt.Errorf("Illegal hardlink in archive: %s -> %s (points outside the destination)", name, target)
+	}
+	if info, err := os.Lstat(targetPath); err != nil || !info.Mode().IsRegular() {
+		return fmt.Errorf("Illegal hardlink in archive: %s -> %s (target is not an extracted regular file)", name, target)
+	}
+
+	path, err := e.prepare(name)
+	if err != nil {
+		return err
+	}
+	return os.Link(targetPath, path)
 }
@@ -0,0 +1,313 @@
+/*
+File: define_archive_test.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-19 10:58:17
+
+Description: 解压压缩包时路径逃逸、链接和大小限制检查的测试
+*/
+
+package general
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// archiveEntry 测试压缩包中的单个条目
+type archiveEntry struct {
+	name     string      // 文件名
+	body     string      // 文件内容
+	mode     os.FileMode // 文件权限
+	typeflag byte        // tar 条目类型，zip 只区分文件夹、符号链接和普通文件
+	linkname string      // 链接目标
+}
+
+// writeTarGz 创建 tar.gz 格式的测试压缩包
+func writeTarGz(t *testing.T, path string, entries []archiveEntry) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:     entry.name,
+			Mode:     int64(entry.mode.Perm()),
+			Typeflag: entry.typeflag,
+			Linkname: entry.linkname,
+		}
+		if entry.typeflag == tar.TypeReg {
+			header.Size = int64(len(entry.body))
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if entry.typeflag == tar.TypeReg {
+			if _, err := tarWriter.Write([]byte(entry.body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeZip 创建 zip 格式的测试压缩包
+func writeZip(t *testing.T, path string, entries []archiveEntry) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	zipWriter := zip.NewWriter(file)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate}
+		body := entry.body
+		switch entry.typeflag {
+		case tar.TypeDir:
+			header.SetMode(os.ModeDir | 0755)
+		case tar.TypeSymlink:
+			header.SetMode(os.ModeSymlink | 0777)
+			body = entry.linkname
+		default:
+			header.SetMode(entry.mode)
+		}
+		writer, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnzipFileExtracts(t *testing.T) {
+	entries := []archiveEntry{
+		{name: "app/", typeflag: tar.TypeDir},
+		{name: "app/bin/app", body: "binary", mode: 0755, typeflag: tar.TypeReg},
+		{name: "app/README.md", body: "readme", mode: 0644, typeflag: tar.TypeReg},
+	}
+	for _, fileType := range []string{"tar.gz", "zip"} {
+		dir := t.TempDir()
+		archive := filepath.Join(dir, "app_v1.0.0_linux_amd64."+fileType)
+		if fileType == "zip" {
+			writeZip(t, archive, entries)
+		} else {
+			writeTarGz(t, archive, entries)
+		}
+		if err := UnzipFile(archive, dir); err != nil {
+			t.Fatalf("%s: UnzipFile: %v", fileType, err)
+		}
+
+		root := filepath.Join(dir, "app_v1.0.0_linux_amd64")
+		content, err := os.ReadFile(filepath.Join(root, "app", "bin", "app"))
+		if err != nil || string(content) != "binary" {
+			t.Errorf("%s: extracted binary = %q, %v", fileType, content, err)
+		}
+		if runtime.GOOS != "windows" {
+			if info, err := os.Stat(filepath.Join(root, "app", "bin", "app")); err != nil || info.Mode().Perm() != 0755 {
+				t.Errorf("%s: extracted binary mode = %v, %v, want 0755", fileType, info.Mode().Perm(), err)
+			}
+		}
+	}
+}
+
+func TestUnzipFileRejects(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need extra privileges on Windows")
+	}
+
+	tests := []struct {
+		name    string
+		entries []archiveEntry
+		zip     bool // 是否同时使用 zip 格式测试
+		message string
+	}{
+		{
+			name:    "parent traversal",
+			entries: []archiveEntry{{name: "../evil", body: "x", mode: 0644, typeflag: tar.TypeReg}},
+			zip:     true,
+			message: "Illegal file path",
+		},
+		{
+			name:    "nested parent traversal",
+			entries: []archiveEntry{{name: "app/../../evil", body: "x", mode: 0644, typeflag: tar.TypeReg}},
+			zip:     true,
+			message: "Illegal file path",
+		},
+		{
+			name:    "absolute path",
+			entries: []archiveEntry{{name: "/tmp/evil", body: "x", mode: 0644, typeflag: tar.TypeReg}},
+			message: "Illegal file path",
+		},
+		{
+			name:    "absolute symlink",
+			entries: []archiveEntry{{name: "link", typeflag: tar.TypeSymlink, linkname: "/etc/passwd"}},
+			zip:     true,
+			message: "Illegal symlink",
+		},
+		{
+			name:    "relative symlink escaping",
+			entries: []archiveEntry{{name: "app/link", typeflag: tar.TypeSymlink, linkname: "../../outside"}},
+			zip:     true,
+			message: "Illegal symlink",
+		},
+		{
+			name: "write through symlinked directory",
+			entries: []archiveEntry{
+				{name: "app", typeflag: tar.TypeSymlink, linkname: "real"},
+				{name: "app/evil", body: "x", mode: 0644, typeflag: tar.TypeReg},
+			},
+			zip:     true,
+			message: "parent directory is a symlink",
+		},
+		{
+			name:    "hardlink escaping",
+			entries: []archiveEntry{{name: "link", typeflag: tar.TypeLink, linkname: "../outside"}},
+			message: "Illegal hardlink",
+		},
+		{
+			name:    "hardlink to missing file",
+			entries: []archiveEntry{{name: "link", typeflag: tar.TypeLink, linkname: "missing"}},
+			message: "Illegal hardlink",
+		},
+		{
+			name: "hardlink to symlink",
+			entries: []archiveEntry{
+				{name: "link", typeflag: tar.TypeSymlink, linkname: "file"},
+				{name: "file", body: "x", mode: 0644, typeflag: tar.TypeReg},
+				{name: "hard", typeflag: tar.TypeLink, linkname: "link"},
+			},
+			message: "Illegal hardlink",
+		},
+	}
+
+	for _, test := range tests {
+		formats := []string{"tar.gz"}
+		if test.zip {
+			formats = append(formats, "zip")
+		}
+		for _, fileType := range formats {
+			dir := t.TempDir()
+			outside := filepath.Join(dir, "outside")
+			if err := os.WriteFile(outside, []byte("original"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			archive := filepath.Join(dir, "nested", "app."+fileType)
+			if err := os.MkdirAll(filepath.Dir(archive), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if fileType == "zip" {
+				writeZip(t, archive, test.entries)
+			} else {
+				writeTarGz(t, archive, test.entries)
+			}
+
+			err := UnzipFile(archive, filepath.Dir(archive))
+			if err == nil || !strings.Contains(err.Error(), test.message) {
+				t.Errorf("%s (%s): UnzipFile error = %v, want %q", test.name, fileType, err, test.message)
+			}
+			if content, _ := os.ReadFile(outside); string(content) != "original" {
+				t.Errorf("%s (%s): file outside the destination was modified", test.name, fileType)
+			}
+			if FileExist(filepath.Join(dir, "evil")) || FileExist(filepath.Join(dir, "nested", "evil")) {
+				t.Errorf("%s (%s): file written outside the destination", test.name, fileType)
+			}
+		}
+	}
+}
+
+func TestUnzipFileLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need extra privileges on Windows")
+	}
+
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "app.tar.gz")
+	writeTarGz(t, archive, []archiveEntry{
+		{name: "app/bin/app", body: "binary", mode: 0755, typeflag: tar.TypeReg},
+		{name: "app/current", typeflag: tar.TypeSymlink, linkname: "bin/app"},
+		{name: "app/hard", typeflag: tar.TypeLink, linkname: "app/bin/app"},
+	})
+	if err := UnzipFile(archive, dir); err != nil {
+		t.Fatalf("UnzipFile: %v", err)
+	}
+
+	root := filepath.Join(dir, "app", "app")
+	if target, err := os.Readlink(filepath.Join(root, "current")); err != nil || target != filepath.FromSlash("bin/app") {
+		t.Errorf("symlink target = %q, %v", target, err)
+	}
+	if content, err := os.ReadFile(filepath.Join(root, "hard")); err != nil || string(content) != "binary" {
+		t.Errorf("hardlink content = %q, %v", content, err)
+	}
+}
+
+func TestUnzipFileSizeLimit(t *testing.T) {
+	limit := MaxUncompressedSize
+	MaxUncompressedSize = 10
+	defer func() { MaxUncompressedSize = limit }()
+
+	tests := []struct {
+		name    string
+		entries []archiveEntry
+		ok      bool
+	}{
+		{"within limit", []archiveEntry{{name: "a", body: "12345", mode: 0644, typeflag: tar.TypeReg}, {name: "b", body: "12345", mode: 0644, typeflag: tar.TypeReg}}, true},
+		{"single file over limit", []archiveEntry{{name: "a", body: "12345678901", mode: 0644, typeflag: tar.TypeReg}}, false},
+		{"total over limit", []archiveEntry{{name: "a", body: "123456", mode: 0644, typeflag: tar.TypeReg}, {name: "b", body: "123456", mode: 0644, typeflag: tar.TypeReg}}, false},
+	}
+	for _, test := range tests {
+		for _, fileType := range []string{"tar.gz", "zip"} {
+			dir := t.TempDir()
+			archive := filepath.Join(dir, "app."+fileType)
+			if fileType == "zip" {
+				writeZip(t, archive, test.entries)
+			} else {
+				writeTarGz(t, archive, test.entries)
+			}
+			err := UnzipFile(archive, dir)
+			if test.ok && err != nil {
+				t.Errorf("%s (%s): UnzipFile: %v", test.name, fileType, err)
+			}
+			if !test.ok && (err == nil || !strings.Contains(err.Error(), "maximum uncompressed size")) {
+				t.Errorf("%s (%s): UnzipFile error = %v, want size limit error", test.name, fileType, err)
+			}
+		}
+	}
+}
+
+func TestUnzipFileUnsupported(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string][]byte{
+		"app.exe":    []byte("MZ\x90\x00rest of the file"),
+		"app.tar.gz": []byte("not an archive at all"),
+	}
+	for name, content := range tests {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := UnzipFile(path, dir); err == nil {
+			t.Errorf("UnzipFile(%s) should fail", name)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+/*
+File: define_asset.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-18 09:12:53
+
+Description: Release 文件名模板、架构别名和 C 标准库变体
+*/
+
+package general
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// C 标准库变体
+const (
+	LibcGnu  = "gnu"  // glibc
+	LibcMusl = "musl" // musl
+)
+
+// 默认的 Release 文件名模板
+const (
+	defaultArchiveTemplate   = "{{.Name}}_{{.Tag}}_{{.OS}}_{{.Arch}}"
+	defaultChecksumsTemplate = "checksums.txt"
+	defaultBinaryTemplate    = "{{.Name}}"
+)
+
+// archAliases 架构别名，Release 文件名常用 uname -m 的架构名
+var archAliases = map[string]string{
+	"amd64": "x86_64",
+	"arm64": "aarch64",
+}
+
+// AssetTemplateData Release 文件名模板中可用的变量
+type AssetTemplateData struct {
+	Name    string // 程序名
+	Tag     string // Tag，例如 v1.2.3
+	Version string // 去掉 v 前缀的版本，例如 1.2.3
+	OS      string // 操作系统，例如 linux
+	Arch    string // 系统架构，例如 amd64 或其别名 x86_64
+	Libc    string // C 标准库变体，Linux 平台为 gnu 或 musl，其他平台为空
+}
+
+// ReleaseAssetNames 程序的 Release 文件名
+type ReleaseAssetNames struct {
+	Checksums string            // 校验文件名
+	Archives  []string          // 可能的压缩包文件名，按优先级排列
+	binaries  map[string]string // 压缩包文件名到其中程序路径的映射
+}
+
+// BinaryPath 获取压缩包中程序的相对路径
+//
+// 参数：
+//   - archive: 压缩包文件名
+//
+// 返回：
+//   - 程序在解压得到的文件夹中的相对路径
+func (n ReleaseAssetNames) BinaryPath(archive string) string {
+	return n.binaries[archive]
+}
+
+// ReleaseAssets 根据程序的文件名模板生成 Release 文件名
+//
+//   - 压缩包文件名依次使用系统架构名及其别名（amd64 → x86_64、arm64 → aarch64）、本机的 C 标准库变体及可兼容的变体渲染
+//   - 压缩包模板不带后缀时依次尝试所有支持的压缩包后缀和无需解压的可执行文件
+//
+// 参数：
+//   - program: 程序名
+//   - tag: Tag
+//
+// 返回：
+//   - Release 文件名
+//   - 错误信息
+func (c GoConfig) ReleaseAssets(program, tag string) (ReleaseAssetNames, error) {
+	asset := c.Assets[program]
+	names := ReleaseAssetNames{binaries: make(map[string]string)}
+
+	archs := []string{Arch}
+	if alias, ok := archAliases[Arch]; ok {
+		archs = append(archs, alias)
+	}
+	libcs := compatibleLibcs(asset.Libc)
+
+	for _, arch := range archs {
+		for _, libc := range libcs {
+			data := AssetTemplateData{
+				Name:    program,
+				Tag:     tag,
+				Version: strings.TrimPrefix(tag, "v"),
+				OS:      Platform,
+				Arch:    arch,
+				Libc:    libc,
+			}
+			archive, err := renderAssetTemplate("archive", asset.Archive, defaultArchiveTemplate, data)
+			if err != nil {
+				return names, err
+			}
+			binary, err := renderAssetTemplate("binary", asset.Binary, defaultBinaryTemplate, data)
+			if err != nil {
+				return names, err
+			}
+			if !filepath.IsLocal(binary) {
+				return names, fmt.Errorf("Illegal binary path '%s' in program.go.assets.%s", binary, program)
+			}
+
+			archives := []string{archive}
+			if !hasArchiveType(archive) {
+				archives = ArchiveFileNames(archive)
+			}
+			for _, name := range archives {
+				if _, ok := names.binaries[name]; !ok {
+					names.Archives = append(names.Archives, name)
+					names.binaries[name] = binary
+				}
+			}
+
+			if names.Checksums == "" {
+				if names.Checksums, err = renderAssetTemplate("checksums", asset.Checksums, defaultChecksumsTemplate, data); err != nil {
+					return names, err
+				}
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// validateAssets 检查程序单独设置的 Release 文件名模板是否合法
+//
+// 参数：
+//   - config: 基于 golang 的程序的配置项
+//
+// 返回：
+//   - 错误信息
+func validateAssets(config GoConfig) error {
+	for program, asset := range config.Assets {
+		switch strings.ToLower(asset.Libc) {
+		case "", LibcGnu, LibcMusl:
+		default:
+			return fmt.Errorf("Unsupported libc '%s' in program.go.assets.%s: only '%s' and '%s' are supported", asset.Libc, program, LibcGnu, LibcMusl)
+		}
+		if _, err := config.ReleaseAssets(program, "v0.0.0"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderAssetTemplate 渲染 Release 文件名模板
+//
+// 参数：
+//   - name: 模板名
+//   - text: 模板，为空时使用默认模板
+//   - defaultText: 默认模板
+//   - data: 模板变量
+//
+// 返回：
+//   - 渲染结果
+//   - 错误信息
+func renderAssetTemplate(name, text, defaultText string, data AssetTemplateData) (string, error) {
+	if text == "" {
+		text = defaultText
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("Invalid %s template '%s': %s", name, text, err)
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return "", fmt.Errorf("Invalid %s template '%s': %s", name, text, err)
+	}
+	return buffer.String(), nil
+}
+
+// compatibleLibcs 获取本机可用的 C 标准库变体，按优先级排列
+//
+//   - 非 Linux 平台没有 C 标准库变体
+//   - 使用 glibc 的系统也可以运行静态链接的 musl 程序，反之则不行
+//
+// 参数：
+//   - libc: 程序单独设置的 C 标准库变体，为空时自动检测
+//
+// 返回：
+//   - C 标准库变体
+func compatibleLibcs(libc string) []string {
+	if Platform != "linux" {
+		return []string{""}
+	}
+	if libc != "" {
+		return []string{strings.ToLower(libc)}
+	}
+	if systemLibc() == LibcMusl {
+		return []string{LibcMusl}
+	}
+	return []string{LibcGnu, LibcMusl}
+}
+
+// systemLibc 检测本机使用的 C 标准库变体
+//
+// 返回：
+//   - C 标准库变体，存在 musl 动态链接器时为 musl，否则为 gnu
+func systemLibc() string {
+	for _, pattern := range []string{"/lib/ld-musl-*.so.1", "/usr/lib/ld-musl-*.so.1"} {
+		if matches, _ := filepath.Glob(pattern); len(matches) > 0 {
+			return LibcMusl
+		}
+	}
+	return LibcGnu
+}
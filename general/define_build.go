@@ -0,0 +1,138 @@
+/*
+File: define_build.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-18 11:06:24
+
+Description: source 安装方式的构建配置：构建命令、环境变量、构建标签和输出路径
+*/
+
+package general
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultLdflags 使用 `go build` 构建时默认的链接参数
+const defaultLdflags = "-s -w"
+
+// BuildPlan 构建单个程序的方案
+type BuildPlan struct {
+	Command string   // 构建命令
+	Args    []string // 构建命令参数
+	Env     []string // 构建命令的环境变量（包括当前进程的环境变量）
+	Output  string   // 构建得到的程序相对于仓库根目录的路径
+	UseMake bool     // 是否使用 Makefile 构建，是则使用 `make install` 安装
+}
+
+// BuildPlanOf 获取程序的构建方案，在仓库根目录中调用
+//
+//   - 配置了构建命令时使用该命令，否则 Makefile 存在时使用 make，main.go 存在时使用 `go build`
+//   - 构建标签通过 `go build -tags` 传递，使用 make 或自定义命令时追加到 GOFLAGS 环境变量
+//   - 链接参数只能用于 `go build`，使用 make 构建时设置了链接参数会返回错误
+//
+// 参数：
+//   - program: 程序名
+//   - generatePath: 构建得到的程序的默认存储文件夹
+//
+// 返回：
+//   - 构建方案
+//   - 错误信息
+func (c ProgramConfig) BuildPlanOf(program, generatePath string) (BuildPlan, error) {
+	build := c.Builds[program]
+	plan := BuildPlan{
+		Env:    append(os.Environ(), build.Env...),
+		Output: filepath.FromSlash(build.Output),
+	}
+	if plan.Output == "" {
+		plan.Output = filepath.Join(generatePath, program)
+	}
+
+	switch {
+	case len(build.Command) > 0:
+		plan.Command, plan.Args = build.Command[0], build.Command[1:]
+	case FileExist("Makefile"):
+		if build.Ldflags != "" {
+			return plan, fmt.Errorf("Unable to apply ldflags in program.builds.%s: the repository is built with its Makefile, set the flags in the Makefile or use command instead", program)
+		}
+		plan.Command, plan.Args, plan.UseMake = "make", []string{}, true
+	case FileExist("main.go"):
+		ldflags := build.Ldflags
+		if ldflags == "" {
+			ldflags = defaultLdflags
+		}
+		plan.Command = "go"
+		plan.Args = []string{"build", "-trimpath", fmt.Sprintf("-ldflags=%s", ldflags)}
+		if len(build.Tags) > 0 {
+			plan.Args = append(plan.Args, fmt.Sprintf("-tags=%s", strings.Join(build.Tags, ",")))
+		}
+		plan.Args = append(plan.Args, "-o", plan.Output)
+		return plan, nil
+	default:
+		return plan, fmt.Errorf("%s", UnableToCompileMessage)
+	}
+
+	// make 和自定义命令通过 GOFLAGS 获取构建标签
+	if len(build.Tags) > 0 {
+		goflags := os.Getenv("GOFLAGS")
+		for _, env := range build.Env {
+			if value, found := strings.CutPrefix(env, "GOFLAGS="); found {
+				goflags = value
+			}
+		}
+		goflags = strings.TrimSpace(fmt.Sprintf("%s -tags=%s", goflags, strings.Join(build.Tags, ",")))
+		plan.Env = append(plan.Env, fmt.Sprintf("GOFLAGS=%s", goflags))
+	}
+	return plan, nil
+}
+
+// MakeInstallArgs 获取 `make install` 的参数，使 Makefile 将程序安装到 DESTDIR 下的 ProgramPath
+//
+//   - PREFIX 为 ProgramPath 所在的文件夹（ProgramPath 以 bin 结尾时）或 ProgramPath 本身
+//   - BINDIR 为 ProgramPath，覆盖 Makefile 中写死的安装路径
+//   - DESTDIR 为临时文件夹，安装得到的文件再通过安装事务写入实际路径
+//
+// 参数：
+//   - destDir: 临时安装文件夹
+//
+// 返回：
+//   - `make install` 的参数
+func (c ProgramConfig) MakeInstallArgs(destDir string) []string {
+	prefix := c.ProgramPath
+	if filepath.Base(prefix) == "bin" {
+		prefix = filepath.Dir(prefix)
+	}
+	return []string{
+		"install",
+		fmt.Sprintf("PREFIX=%s", prefix),
+		fmt.Sprintf("BINDIR=%s", c.ProgramPath),
+		fmt.Sprintf("DESTDIR=%s", destDir),
+	}
+}
+
+// validateBuilds 检查程序单独设置的构建配置是否合法
+//
+// 参数：
+//   - config: 程序配置项
+//
+// 返回：
+//   - 错误信息
+func validateBuilds(config ProgramConfig) error {
+	for program, build := range config.Builds {
+		for _, env := range build.Env {
+			if key, _, found := strings.Cut(env, "="); !found || key == "" {
+				return fmt.Errorf("Invalid environment variable '%s' in program.builds.%s: it should be KEY=VALUE", env, program)
+			}
+		}
+		if build.Ldflags != "" && len(build.Command) > 0 {
+			return fmt.Errorf("Unable to apply ldflags in program.builds.%s: it is only used by go build, pass the flags in command instead", program)
+		}
+		if build.Output != "" && !filepath.IsLocal(filepath.FromSlash(build.Output)) {
+			return fmt.Errorf("Illegal output path '%s' in program.builds.%s: it should be relative to the repository", build.Output, program)
+		}
+	}
+	return nil
+}
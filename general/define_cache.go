@@ -0,0 +1,159 @@
+/*
+File: define_cache.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-18 00:12:36
+
+Description: API 响应的磁盘缓存，使用 ETag/Last-Modified 发送条件请求
+*/
+
+package general
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gookit/color"
+)
+
+var (
+	apiCachePath    = ""    // API 响应缓存路径，为空时不使用缓存
+	apiCacheRefresh = false // 是否忽略已缓存的响应
+)
+
+// apiCacheEntry 单个 API 响应的缓存
+type apiCacheEntry struct {
+	Url          string    `json:"url"`           // API 地址
+	Identity     string    `json:"identity"`      // 请求使用的认证身份，见 apiCacheIdentity
+	ETag         string    `json:"etag"`          // 响应头 ETag
+	LastModified string    `json:"last_modified"` // 响应头 Last-Modified
+	CachedTime   time.Time `json:"cached_time"`   // 缓存时间
+	Body         []byte    `json:"body"`          // 响应数据
+	Next         string    `json:"next"`          // 下一页的地址，没有下一页时为空
+}
+
+// SetupApiCache 设置 API 响应缓存
+//
+// 参数：
+//   - cachePath: 缓存路径，为空时不使用缓存
+//   - refresh: 是否忽略已缓存的响应，重新请求完整数据
+func SetupApiCache(cachePath string, refresh bool) {
+	apiCachePath = cachePath
+	apiCacheRefresh = refresh
+}
+
+// apiCacheIdentity 获取请求的认证身份，不同 Token 的响应分开缓存
+//
+// 参数：
+//   - req: HTTP 请求
+//
+// 返回：
+//   - Authorization 请求头的 SHA-256 校验和，未认证时为空
+func apiCacheIdentity(req *http.Request) string {
+	authorization := req.Header.Get("Authorization")
+	if authorization == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(authorization))
+	return hex.EncodeToString(sum[:])
+}
+
+// apiCacheFile 获取 API 地址和认证身份对应的缓存文件路径
+//
+// 参数：
+//   - url: API 地址
+//   - identity: 认证身份
+//
+// 返回：
+//   - 缓存文件路径
+func apiCacheFile(url, identity string) string {
+	sum := sha256.Sum256([]byte(color.Sprintf("%s\n%s", identity, url)))
+	return filepath.Join(apiCachePath, color.Sprintf("%s.json", hex.EncodeToString(sum[:])))
+}
+
+// loadApiCache 读取 API 地址的缓存并为请求设置条件请求头
+//
+// 参数：
+//   - req: HTTP 请求
+//
+// 返回：
+//   - 缓存，没有可用的缓存时为 nil
+func loadApiCache(req *http.Request) *apiCacheEntry {
+	if apiCachePath == "" || apiCacheRefresh {
+		return nil
+	}
+
+	url, identity := req.URL.String(), apiCacheIdentity(req)
+	content, err := os.ReadFile(apiCacheFile(url, identity))
+	if err != nil {
+		return nil
+	}
+	entry := &apiCacheEntry{}
+	// 缓存损坏或哈希冲突时视为没有缓存
+	if err := json.Unmarshal(content, entry); err != nil || entry.Url != url || entry.Identity != identity {
+		return nil
+	}
+
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+	if entry.ETag == "" && entry.LastModified == "" {
+		return nil
+	}
+
+	return entry
+}
+
+// saveApiCache 缓存 API 响应，没有 ETag 和 Last-Modified 的响应不缓存
+//
+//   - 响应可能包含私有仓库的信息，缓存只允许当前用户读写
+//
+// 参数：
+//   - req: HTTP 请求
+//   - header: 响应头
+//   - body: 响应数据
+//   - next: 下一页的地址
+func saveApiCache(req *http.Request, header http.Header, body []byte, next string) {
+	if apiCachePath == "" {
+		return
+	}
+
+	entry := apiCacheEntry{
+		Url:          req.URL.String(),
+		Identity:     apiCacheIdentity(req),
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		CachedTime:   time.Now().Truncate(time.Second),
+		Body:         body,
+		Next:         next,
+	}
+	if entry.ETag == "" && entry.LastModified == "" {
+		return
+	}
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	// 缓存写入失败（例如没有写权限）不影响本次请求
+	if err := os.MkdirAll(apiCachePath, 0700); err != nil {
+		return
+	}
+	cacheFile := apiCacheFile(entry.Url, entry.Identity)
+	tempFile := color.Sprintf("%s.tmp", cacheFile)
+	if err := os.WriteFile(tempFile, content, 0600); err != nil {
+		os.Remove(tempFile)
+		return
+	}
+	if err := os.Rename(tempFile, cacheFile); err != nil {
+		os.Remove(tempFile)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+File: define_channel.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-18 03:58:41
+
+Description: 发布渠道，决定是否安装先行版本
+*/
+
+package general
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 发布渠道
+const (
+	ChannelStable     = "stable"     // 正式版本
+	ChannelPrerelease = "prerelease" // 包括先行版本（例如 -rc）
+)
+
+// ChannelOf 获取程序遵循的发布渠道，优先使用程序单独的设置，其次是全局设置，都未设置时为正式版本
+//
+// 参数：
+//   - program: 程序名
+//
+// 返回：
+//   - 发布渠道
+func (c GoConfig) ChannelOf(program string) string {
+	if channel := c.Channels[program]; channel != "" {
+		return strings.ToLower(channel)
+	}
+	if c.Channel != "" {
+		return strings.ToLower(c.Channel)
+	}
+	return ChannelStable
+}
+
+// validateChannels 检查全局和程序单独设置的发布渠道是否合法
+//
+// 参数：
+//   - config: 基于 golang 的程序的配置项
+//
+// 返回：
+//   - 错误信息
+func validateChannels(config GoConfig) error {
+	settings := map[string]string{"channel": config.Channel}
+	for program, channel := range config.Channels {
+		settings[fmt.Sprintf("channels.%s", program)] = channel
+	}
+	for key, channel := range settings {
+		switch strings.ToLower(channel) {
+		case "", ChannelStable, ChannelPrerelease:
+		default:
+			return fmt.Errorf("Unsupported channel '%s' in program.go.%s: only '%s' and '%s' are supported", channel, key, ChannelStable, ChannelPrerelease)
+		}
+	}
+	return nil
+}
+
+// HighestRelease 选出 Release 列表中语义化版本最高的 Release
+//
+// 参数：
+//   - releases: Release 列表
+//   - includePrerelease: 是否包括先行版本
+//
+// 返回：
+//   - 最高版本的 Release
+//   - 错误信息
+func HighestRelease(releases []Release, includePrerelease bool) (Release, error) {
+	tags := make([]string, 0, len(releases))
+	for _, release := range releases {
+		tags = append(tags, release.Tag)
+	}
+
+	tag := HighestTag(tags, includePrerelease)
+	for _, release := range releases {
+		if tag != "" && release.Tag == tag {
+			return release, nil
+		}
+	}
+	return Release{}, fmt.Errorf("No release found")
+}
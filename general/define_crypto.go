@@ -12,30 +12,82 @@ package general
 import (
 	"bufio"
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"unicode"
+
+	"golang.org/x/crypto/blake2b"
 )
 
-// FileSHA256 计算文件的 SHA-256 校验和
+// 校验和算法
+const (
+	ChecksumSHA256     = "sha256"      // SHA-256
+	ChecksumSHA512     = "sha512"      // SHA-512
+	ChecksumBLAKE2b256 = "blake2b-256" // BLAKE2b-256（b2sum -l 256）
+	ChecksumBLAKE2b512 = "blake2b-512" // BLAKE2b-512（b2sum 默认）
+)
+
+// bsdChecksumLine BSD 格式（以及 OpenSSL 格式）的校验和行，例如 SHA256 (file) = hash
+var bsdChecksumLine = regexp.MustCompile(`^([A-Za-z0-9-]+) ?\((.+)\) ?= ?([0-9A-Fa-f]+)$`)
+
+// checksumEntry 校验和文件中的一条记录
+type checksumEntry struct {
+	algorithm string // 校验和算法，BSD 格式的行会指定，GNU 格式的行为空
+	checksum  string // 校验和（小写）
+	filename  string // 文件名
+}
+
+// newHash 创建指定算法的 Hash
+//
+// 参数：
+//   - algorithm: 校验和算法
+//
+// 返回：
+//   - Hash
+//   - 错误信息
+func newHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case ChecksumSHA256:
+		return sha256.New(), nil
+	case ChecksumSHA512:
+		return sha512.New(), nil
+	case ChecksumBLAKE2b256:
+		return blake2b.New256(nil)
+	case ChecksumBLAKE2b512:
+		return blake2b.New512(nil)
+	default:
+		return nil, fmt.Errorf("Unsupported checksum algorithm '%s'", algorithm)
+	}
+}
+
+// FileChecksum 使用指定算法计算文件的校验和
 //
 // 参数：
 //   - filePath: 待校验文件
+//   - algorithm: 校验和算法
 //
 // 返回：
 //   - 校验和
 //   - 错误信息
-func FileSHA256(filePath string) (string, error) {
+func FileChecksum(filePath, algorithm string) (string, error) {
+	hash, err := newHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+
 	file, err := os.Open(filePath)
 	if err != nil {
 		return "", err
 	}
 	defer file.Close()
 
-	hash := sha256.New()
 	if _, err := io.Copy(hash, file); err != nil {
 		return "", err
 	}
@@ -45,8 +97,23 @@ func FileSHA256(filePath string) (string, error) {
 	return checksum, nil
 }
 
+// FileSHA256 计算文件的 SHA-256 校验和
+//
+// 参数：
+//   - filePath: 待校验文件
+//
+// 返回：
+//   - 校验和
+//   - 错误信息
+func FileSHA256(filePath string) (string, error) {
+	return FileChecksum(filePath, ChecksumSHA256)
+}
+
 // FileVerification 使用校验和文件校验文件的完整性
 //
+//   - 支持 GNU 格式（<checksum>  <filename>，二进制模式的文件名带 * 前缀）和 BSD 格式（SHA256 (filename) = <checksum>）
+//   - BSD 格式的行自带算法，GNU 格式的行根据校验和文件名（例如 sha512sums.txt）或校验和长度选择算法
+//
 // 参数：
 //   - checksumFile: 校验和文件
 //   - filePath: 待校验文件
@@ -73,34 +140,39 @@ func FileVerification(checksumFile, filePath string) (bool, error) {
 
 	// 扫描处理校验文件
 	scanner := bufio.NewScanner(file)
+	lineNo := 0
 	for scanner.Scan() {
-		// 按行获取校验文件内容
-		line := scanner.Text()
-		// 以空格分割行
-		lineParts := strings.Fields(line)
-
-		if len(lineParts) == 2 {
-			expectedChecksum := lineParts[0] // 期望的校验和
-			filename := lineParts[1]         // 文件名
-
-			// 检测校验文件中是否记载了指定文件的校验和信息
-			if filename == filepath.Base(filePath) {
-				// 计算文件的实际校验和
-				actualChecksum, err := FileSHA256(filePath)
-				if err != nil {
-					return false, err
-				}
-
-				// 比对校验和
-				if actualChecksum == expectedChecksum {
-					return true, nil
-				} else {
-					return false, nil
-				}
-			}
+		lineNo++
+		// 按行获取校验文件内容，跳过空行和注释
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
-		return false, fmt.Errorf("Checksum file format error, it should be: <checksum> <filename>")
+
+		entry, ok := parseChecksumLine(line)
+		if !ok {
+			return false, fmt.Errorf("Checksum file format error at line %d, it should be: <checksum>  <filename> or <ALGORITHM> (<filename>) = <checksum>", lineNo)
+		}
+
+		// 检测校验文件中是否记载了指定文件的校验和信息
+		if filepath.Base(entry.filename) != filepath.Base(filePath) {
+			continue
+		}
+		algorithm := entry.algorithm
+		if algorithm == "" {
+			if algorithm, err = detectChecksumAlgorithm(checksumFile, entry.checksum); err != nil {
+				return false, err
+			}
+		}
+
+		// 计算文件的实际校验和
+		actualChecksum, err := FileChecksum(filePath, algorithm)
+		if err != nil {
+			return false, err
+		}
+
+		// 比对校验和
+		return actualChecksum == entry.checksum, nil
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -109,3 +181,124 @@ func FileVerification(checksumFile, filePath string) (bool, error) {
 
 	return false, nil
 }
+
+// parseChecksumLine 解析校验和文件中的一行
+//
+// 参数：
+//   - line: 校验和文件中的一行
+//
+// 返回：
+//   - 校验和记录
+//   - 是否为合法的校验和行
+func parseChecksumLine(line string) (checksumEntry, bool) {
+	// BSD 格式：SHA256 (filename) = checksum
+	if matches := bsdChecksumLine.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+		algorithm := normalizeChecksumAlgorithm(matches[1], len(matches[3]))
+		if algorithm == "" {
+			return checksumEntry{}, false
+		}
+		return checksumEntry{algorithm: algorithm, checksum: strings.ToLower(matches[3]), filename: matches[2]}, true
+	}
+
+	// GNU 格式：checksum  filename 或 checksum *filename，也接受以制表符或多个空格分隔
+	line = strings.TrimSpace(line)
+	index := strings.IndexFunc(line, unicode.IsSpace)
+	if index < 0 {
+		return checksumEntry{}, false
+	}
+	checksum := line[:index]
+	filename := strings.TrimPrefix(strings.TrimLeftFunc(line[index:], unicode.IsSpace), "*")
+	if !isHex(checksum) || filename == "" {
+		return checksumEntry{}, false
+	}
+	return checksumEntry{checksum: strings.ToLower(checksum), filename: filename}, true
+}
+
+// normalizeChecksumAlgorithm 将 BSD/OpenSSL 格式中的算法名转换为校验和算法
+//
+// 参数：
+//   - name: 算法名，例如 SHA256、SHA2-512、BLAKE2b、BLAKE2b-256
+//   - length: 校验和的十六进制长度
+//
+// 返回：
+//   - 校验和算法，不支持时为空
+func normalizeChecksumAlgorithm(name string, length int) string {
+	switch strings.ToLower(name) {
+	case "sha256", "sha2-256":
+		return ChecksumSHA256
+	case "sha512", "sha2-512":
+		return ChecksumSHA512
+	case "blake2b":
+		return blake2bAlgorithm(length)
+	case "blake2b-256":
+		return ChecksumBLAKE2b256
+	case "blake2b-512", "blake2b512":
+		return ChecksumBLAKE2b512
+	default:
+		return ""
+	}
+}
+
+// detectChecksumAlgorithm 根据校验和文件名或校验和长度选择算法
+//
+// 参数：
+//   - checksumFile: 校验和文件
+//   - checksum: 校验和
+//
+// 返回：
+//   - 校验和算法
+//   - 错误信息
+func detectChecksumAlgorithm(checksumFile, checksum string) (string, error) {
+	name := strings.ToLower(filepath.Base(checksumFile))
+	switch {
+	case strings.Contains(name, "sha512"):
+		return ChecksumSHA512, nil
+	case strings.Contains(name, "sha256"):
+		return ChecksumSHA256, nil
+	case strings.Contains(name, "blake2") || strings.Contains(name, "b2sum"):
+		if algorithm := blake2bAlgorithm(len(checksum)); algorithm != "" {
+			return algorithm, nil
+		}
+	default:
+		switch len(checksum) {
+		case sha256.Size * 2:
+			return ChecksumSHA256, nil
+		case sha512.Size * 2:
+			return ChecksumSHA512, nil
+		}
+	}
+	return "", fmt.Errorf("Unable to determine checksum algorithm from file name %s and checksum length %d", filepath.Base(checksumFile), len(checksum))
+}
+
+// blake2bAlgorithm 根据校验和长度选择 BLAKE2b 算法
+//
+// 参数：
+//   - length: 校验和的十六进制长度
+//
+// 返回：
+//   - 校验和算法，长度不支持时为空
+func blake2bAlgorithm(length int) string {
+	switch length {
+	case blake2b.Size256 * 2:
+		return ChecksumBLAKE2b256
+	case blake2b.Size * 2:
+		return ChecksumBLAKE2b512
+	default:
+		return ""
+	}
+}
+
+// isHex 检查字符串是否为非空的十六进制字符串
+//
+// 参数：
+//   - text: 字符串
+//
+// 返回：
+//   - 是否为十六进制字符串
+func isHex(text string) bool {
+	if text == "" {
+		return false
+	}
+	_, err := hex.DecodeString(text)
+	return err == nil
+}
@@ -0,0 +1,152 @@
+/*
+File: define_crypto_test.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-19 10:31:05
+
+Description: 校验和文件解析、算法检测和文件校验的测试
+*/
+
+package general
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// "abc" 的校验和
+const (
+	abcSHA256     = "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	abcSHA512     = "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f"
+	abcBLAKE2b256 = "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319"
+	abcBLAKE2b512 = "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"
+)
+
+func TestParseChecksumLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want checksumEntry
+		ok   bool
+	}{
+		{"gnu text mode", abcSHA256 + "  app.tar.gz", checksumEntry{checksum: abcSHA256, filename: "app.tar.gz"}, true},
+		{"gnu binary mode", abcSHA256 + " *app.tar.gz", checksumEntry{checksum: abcSHA256, filename: "app.tar.gz"}, true},
+		{"single space", abcSHA256 + " app.tar.gz", checksumEntry{checksum: abcSHA256, filename: "app.tar.gz"}, true},
+		{"tab", abcSHA256 + "\tapp.tar.gz", checksumEntry{checksum: abcSHA256, filename: "app.tar.gz"}, true},
+		{"several spaces", abcSHA256 + "    app.tar.gz", checksumEntry{checksum: abcSHA256, filename: "app.tar.gz"}, true},
+		{"tab and binary mode", abcSHA256 + "\t*app.tar.gz", checksumEntry{checksum: abcSHA256, filename: "app.tar.gz"}, true},
+		{"upper case checksum", strings.ToUpper(abcSHA256) + "  app.tar.gz", checksumEntry{checksum: abcSHA256, filename: "app.tar.gz"}, true},
+		{"trailing whitespace", abcSHA256 + "  app.tar.gz \r", checksumEntry{checksum: abcSHA256, filename: "app.tar.gz"}, true},
+		{"bsd sha256", "SHA256 (app.tar.gz) = " + abcSHA256, checksumEntry{algorithm: ChecksumSHA256, checksum: abcSHA256, filename: "app.tar.gz"}, true},
+		{"bsd sha512", "SHA512 (app.tar.gz) = " + abcSHA512, checksumEntry{algorithm: ChecksumSHA512, checksum: abcSHA512, filename: "app.tar.gz"}, true},
+		{"bsd blake2b by length", "BLAKE2b (app.tar.gz) = " + abcBLAKE2b256, checksumEntry{algorithm: ChecksumBLAKE2b256, checksum: abcBLAKE2b256, filename: "app.tar.gz"}, true},
+		{"openssl", "SHA2-256(app.tar.gz)= " + abcSHA256, checksumEntry{algorithm: ChecksumSHA256, checksum: abcSHA256, filename: "app.tar.gz"}, true},
+		{"bsd unsupported algorithm", "MD5 (app.tar.gz) = 900150983cd24fb0d6963f7d28e17f72", checksumEntry{}, false},
+		{"no filename", abcSHA256, checksumEntry{}, false},
+		{"binary marker only", abcSHA256 + " *", checksumEntry{}, false},
+		{"not hex", "checksum  app.tar.gz", checksumEntry{}, false},
+	}
+	for _, test := range tests {
+		got, ok := parseChecksumLine(test.line)
+		if ok != test.ok {
+			t.Errorf("%s: parseChecksumLine(%q) ok = %v, want %v", test.name, test.line, ok, test.ok)
+			continue
+		}
+		if ok && got != test.want {
+			t.Errorf("%s: parseChecksumLine(%q) = %+v, want %+v", test.name, test.line, got, test.want)
+		}
+	}
+}
+
+func TestDetectChecksumAlgorithm(t *testing.T) {
+	tests := []struct {
+		checksumFile string
+		checksum     string
+		want         string
+		ok           bool
+	}{
+		{"checksums.txt", abcSHA256, ChecksumSHA256, true},
+		{"checksums.txt", abcSHA512, ChecksumSHA512, true},
+		{"checksums.txt", "abcd", "", false},
+		{"SHA512SUMS", abcSHA512, ChecksumSHA512, true},
+		{"app_sha256sums.txt", abcSHA256, ChecksumSHA256, true},
+		{"B2SUMS", abcBLAKE2b512, ChecksumBLAKE2b512, true},
+		{"app.blake2b", abcBLAKE2b256, ChecksumBLAKE2b256, true},
+		{"app.blake2b", "abcd", "", false},
+	}
+	for _, test := range tests {
+		got, err := detectChecksumAlgorithm(test.checksumFile, test.checksum)
+		if (err == nil) != test.ok {
+			t.Errorf("detectChecksumAlgorithm(%q, %d chars) error = %v, want ok = %v", test.checksumFile, len(test.checksum), err, test.ok)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("detectChecksumAlgorithm(%q, %d chars) = %q, want %q", test.checksumFile, len(test.checksum), got, test.want)
+		}
+	}
+}
+
+func TestFileChecksum(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "abc")
+	if err := os.WriteFile(file, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		ChecksumSHA256:     abcSHA256,
+		ChecksumSHA512:     abcSHA512,
+		ChecksumBLAKE2b256: abcBLAKE2b256,
+		ChecksumBLAKE2b512: abcBLAKE2b512,
+	}
+	for algorithm, want := range tests {
+		got, err := FileChecksum(file, algorithm)
+		if err != nil || got != want {
+			t.Errorf("FileChecksum(%s) = %q, %v, want %q", algorithm, got, err, want)
+		}
+	}
+	if _, err := FileChecksum(file, "md5"); err == nil {
+		t.Errorf("FileChecksum(md5) should fail")
+	}
+}
+
+func TestFileVerification(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.tar.gz")
+	if err := os.WriteFile(file, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		checksumFile string
+		content      string
+		want         bool
+		ok           bool
+	}{
+		{"gnu sha256", "checksums.txt", "# comment\n\n" + abcSHA512 + "  other.tar.gz\n" + abcSHA256 + "  app.tar.gz\n", true, true},
+		{"gnu tab separated", "checksums.txt", abcSHA256 + "\tapp.tar.gz\n", true, true},
+		{"gnu crlf", "checksums.txt", abcSHA256 + " *app.tar.gz\r\n", true, true},
+		{"gnu sha512 by file name", "SHA512SUMS", abcSHA512 + "  app.tar.gz\n", true, true},
+		{"b2sum", "B2SUMS", abcBLAKE2b512 + "  app.tar.gz\n", true, true},
+		{"bsd", "checksums.txt", "SHA256 (app.tar.gz) = " + abcSHA256 + "\n", true, true},
+		{"path in checksum file", "checksums.txt", abcSHA256 + "  dist/app.tar.gz\n", true, true},
+		{"mismatch", "checksums.txt", strings.Repeat("0", 64) + "  app.tar.gz\n", false, true},
+		{"not listed", "checksums.txt", abcSHA256 + "  other.tar.gz\n", false, true},
+		{"format error", "checksums.txt", "not a checksum line\n", false, false},
+	}
+	for _, test := range tests {
+		checksumFile := filepath.Join(dir, test.checksumFile)
+		if err := os.WriteFile(checksumFile, []byte(test.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := FileVerification(checksumFile, file)
+		if (err == nil) != test.ok {
+			t.Errorf("%s: FileVerification error = %v, want ok = %v", test.name, err, test.ok)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: FileVerification = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
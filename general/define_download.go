@@ -10,17 +10,38 @@ Description: 文件下载
 package general
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/cheggaaa/pb/v3"
+	"github.com/gookit/color"
 )
 
+var (
+	downloadMaxAttempts  = 5                // 下载的最大尝试次数
+	downloadBackoffBase  = time.Second      // 重试的初始等待时间，之后每次翻倍
+	downloadStallTimeout = 30 * time.Second // 超过该时间没有收到数据则中断本次下载
+	partialRetainTime    = 24 * time.Hour   // 未完成的下载文件的保留时间
+)
+
+// partialSuffix 未完成的下载文件的后缀
+const partialSuffix = ".part"
+
+// validatorSuffix 记录未完成的下载文件对应的 ETag/Last-Modified 的文件后缀
+const validatorSuffix = ".part.validator"
+
 // DownloadFile 通过 HTTP 协议下载文件
 //
+//   - 数据先写入 <outputFile>.part，完成后再重命名为 outputFile
+//   - 已存在 .part 文件时使用 Range 请求续传，远端文件已变化（If-Range 不匹配）时重新下载
+//   - 失败或超过 downloadStallTimeout 没有收到数据时按指数退避重试，最多尝试 downloadMaxAttempts 次
+//
 // 参数：
 //   - url: 文件下载地址
 //   - outputFile: 下载文件保存路径
@@ -29,55 +50,229 @@ import (
 // 返回：
 //   - 错误信息
 func DownloadFile(url string, outputFile string, progressParameters map[string]string) error {
+	// 创建下载文件夹
+	dir := filepath.Dir(outputFile)
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("Error creating download folder: %s", err)
+		}
+	}
+
+	var bar *pb.ProgressBar // 进度条，在多次尝试间共用
+	defer func() {
+		if bar != nil {
+			bar.Finish()
+		}
+	}()
+
+	var err error
+	for attempt := 1; attempt <= downloadMaxAttempts; attempt++ {
+		if attempt > 1 {
+			backoff := downloadBackoffBase << (attempt - 2)
+			color.Warn.Tips("Download %s failed (%s), retry %d/%d in %s", filepath.Base(outputFile), err, attempt-1, downloadMaxAttempts-1, backoff)
+			time.Sleep(backoff)
+		}
+
+		var retry bool
+		retry, err = downloadAttempt(url, outputFile, progressParameters, &bar)
+		if err == nil {
+			// 下载完成，替换为正式文件
+			os.Remove(outputFile + validatorSuffix)
+			if err := os.Rename(outputFile+partialSuffix, outputFile); err != nil {
+				return fmt.Errorf("Error renaming download file: %s", err)
+			}
+			return nil
+		}
+		if !retry {
+			return err
+		}
+	}
+
+	return err
+}
+
+// downloadAttempt 尝试一次下载，从 .part 文件已有的长度处续传
+//
+// 参数：
+//   - url: 文件下载地址
+//   - outputFile: 下载文件保存路径
+//   - progressParameters: 进度条参数
+//   - bar: 进度条，为 nil 且需要显示进度条时创建
+//
+// 返回：
+//   - 失败时是否值得重试
+//   - 错误信息
+func downloadAttempt(url string, outputFile string, progressParameters map[string]string, bar **pb.ProgressBar) (bool, error) {
+	partFile := outputFile + partialSuffix
+	validatorFile := outputFile + validatorSuffix
+
+	// 已下载的长度和远端文件的标识
+	var offset int64
+	validator := ""
+	if info, err := os.Stat(partFile); err == nil {
+		offset = info.Size()
+		if content, err := os.ReadFile(validatorFile); err == nil {
+			validator = strings.TrimSpace(string(content))
+		}
+	}
+	// 没有标识时无法确认远端文件未变化，不续传
+	if validator == "" {
+		offset = 0
+	}
+
+	// 超过 downloadStallTimeout 没有收到数据则取消请求
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stallTimer := time.AfterFunc(downloadStallTimeout, cancel)
+	defer stallTimer.Stop()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, fmt.Errorf("Error sending download request: %s", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", color.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", validator)
+	}
+
 	// 发送GET请求并获取响应
-	resp, err := http.Get(url)
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("Error sending download request: %s", err)
+		return true, fmt.Errorf("Error sending download request: %s", err)
 	}
 	defer resp.Body.Close()
 
 	// 检查返回值状态码
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Error downloading file: %s", resp.Status)
-	}
-
-	// 创建下载文件夹
-	dir := filepath.Dir(outputFile)
-	if dir != "" {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("Error creating download folder: %s", err)
+	flag := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0 && strings.HasPrefix(resp.Header.Get("Content-Range"), color.Sprintf("bytes %d-", offset)):
+		// 续传
+	case resp.StatusCode == http.StatusOK:
+		// 从头下载
+		offset = 0
+		flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		validator = firstNonEmpty(resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"))
+		if validator != "" && !strings.HasPrefix(validator, "W/") {
+			os.WriteFile(validatorFile, []byte(validator), 0644)
+		} else {
+			os.Remove(validatorFile)
 		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// .part 文件无效，删除后重新下载
+		os.Remove(partFile)
+		os.Remove(validatorFile)
+		return true, fmt.Errorf("Error downloading file: %s", resp.Status)
+	default:
+		// 服务端错误和限流值得重试，其他错误（例如 404）不值得重试
+		retry := resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("Error downloading file: %s", resp.Status)
 	}
 
-	// 创建本地文件
-	file, err := os.Create(outputFile)
+	// 打开本地文件
+	file, err := os.OpenFile(partFile, flag, 0644)
 	if err != nil {
-		return fmt.Errorf("Error creating download file: %s", err)
+		return false, fmt.Errorf("Error creating download file: %s", err)
 	}
 	defer file.Close()
 
-	if progressParameters["view"] == "0" {
-		// 将响应主体复制到文件
-		if _, err = io.Copy(file, resp.Body); err != nil {
-			return fmt.Errorf("Error writing download file: %s", err)
+	// 每次收到数据时重置超时
+	var reader io.Reader = &stallReader{reader: resp.Body, timer: stallTimer, timeout: downloadStallTimeout}
+
+	if progressParameters["view"] != "0" {
+		total := int64(-1)
+		if resp.ContentLength >= 0 {
+			total = offset + resp.ContentLength
+		}
+		if *bar == nil {
+			// 创建进度条模板
+			barTemplate := `{{string . "action" | green}} {{string . "prefix"}} {{string . "project" | blue}} {{string . "sep" | blue}} {{string . "fileName" | blue}} {{string . "suffix"}} {{bar . "[" "-" ">" " " "]"}} {{percent . "%.01f%%" "?"}} {{counters . "%s/%s" "%s/?" | green}} {{speed . | yellow}}`
+			// 使用自定义模板创建进度条
+			*bar = pb.New64(total).SetTemplate(pb.ProgressBarTemplate(barTemplate))
+			(*bar).Set(pb.Bytes, true)
+			(*bar).Set("action", progressParameters["action"]).Set("prefix", progressParameters["prefix"]).Set("project", progressParameters["project"]).Set("sep", progressParameters["sep"]).Set("fileName", progressParameters["fileName"]).Set("suffix", progressParameters["suffix"])
+			(*bar).SetCurrent(offset)
+			(*bar).Start()
+		} else {
+			(*bar).SetTotal(total)
+			(*bar).SetCurrent(offset)
 		}
-	} else {
-		// 创建进度条模板
-		barTemplate := `{{string . "action" | green}} {{string . "prefix"}} {{string . "project" | blue}} {{string . "sep" | blue}} {{string . "fileName" | blue}} {{string . "suffix"}} {{bar . "[" "-" ">" " " "]"}} {{percent . "%.01f%%" "?"}} {{counters . "%s/%s" "%s/?" | green}} {{speed . | yellow}}`
-		// 使用自定义模板创建进度条
-		bar := pb.ProgressBarTemplate(barTemplate).Start64(resp.ContentLength)
-		bar.Set(pb.Bytes, true)
-		bar.Set("action", progressParameters["action"]).Set("prefix", progressParameters["prefix"]).Set("project", progressParameters["project"]).Set("sep", progressParameters["sep"]).Set("fileName", progressParameters["fileName"]).Set("suffix", progressParameters["suffix"])
 		// 使用代理读取响应主体
-		reader := bar.NewProxyReader(resp.Body)
+		reader = (*bar).NewProxyReader(reader)
+	}
+
+	// 将响应主体复制到文件
+	written, err := io.Copy(file, reader)
+	if err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("no data received for %s", downloadStallTimeout)
+		}
+		return true, fmt.Errorf("Error writing download file: %s", err)
+	}
+	if resp.ContentLength >= 0 && written < resp.ContentLength {
+		return true, fmt.Errorf("Error writing download file: %s", io.ErrUnexpectedEOF)
+	}
+	if err := file.Sync(); err != nil {
+		return false, fmt.Errorf("Error writing download file: %s", err)
+	}
+
+	return false, nil
+}
+
+// stallReader 每次读到数据时重置超时计时器
+type stallReader struct {
+	reader  io.Reader     // 被包装的 Reader
+	timer   *time.Timer   // 超时计时器
+	timeout time.Duration // 超时时间
+}
+
+// Read 实现 io.Reader 接口
+func (r *stallReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+	return n, err
+}
+
+// IsResumableDownload 判断文件是否为 partialRetainTime 内未完成、可以续传的下载文件（包括其 ETag/Last-Modified 记录）
+//
+// 参数：
+//   - entry: 下载文件夹中的条目
+//
+// 返回：
+//   - 是否为可以续传的下载文件
+func IsResumableDownload(entry os.DirEntry) bool {
+	if entry.IsDir() || !(strings.HasSuffix(entry.Name(), partialSuffix) || strings.HasSuffix(entry.Name(), validatorSuffix)) {
+		return false
+	}
+	info, err := entry.Info()
+	return err == nil && time.Since(info.ModTime()) < partialRetainTime
+}
 
-		// 将响应主体复制到文件
-		if _, err = io.Copy(file, reader); err != nil {
-			return fmt.Errorf("Error writing download file: %s", err)
+// CleanDownloadDir 清空下载文件夹，保留 partialRetainTime 内未完成的下载文件以便续传
+//
+// 参数：
+//   - dir: 下载文件夹
+//
+// 返回：
+//   - 错误信息
+func CleanDownloadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
 		}
+		return err
+	}
 
-		// 完成进度条
-		bar.Finish()
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if IsResumableDownload(entry) {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -0,0 +1,180 @@
+/*
+File: define_forge.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-18 01:42:18
+
+Description: 代码托管平台接口，屏蔽 GitHub、Gitea/Forgejo 和 GitLab 的 API 差异
+*/
+
+package general
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+// apiMaxPages 分页 API 最多请求的页数，每页 100 项
+const apiMaxPages = 50
+
+// Release 与代码托管平台无关的 Release 信息
+type Release struct {
+	Tag    string         // Release 对应的 Tag
+	Assets []ReleaseAsset // Release 文件
+}
+
+// ReleaseAsset 单个 Release 文件
+type ReleaseAsset struct {
+	Name          string  // 文件名
+	Size          float64 // 文件大小
+	ContentType   string  // 文件类型
+	DownloadUrl   string  // 下载地址
+	DownloadCount float64 // 下载次数
+}
+
+// Forge 代码托管平台
+type Forge interface {
+	// Mirror 获取该平台对应的镜像配置
+	Mirror() MirrorConfig
+	// LatestRelease 获取仓库最新的 Release
+	LatestRelease(repo string) (Release, error)
+	// ReleaseByTag 获取仓库指定 Tag 的 Release
+	ReleaseByTag(repo, tag string) (Release, error)
+	// ListReleases 获取仓库的 Release 列表（包括先行版本，不包括草稿）
+	ListReleases(repo string) ([]Release, error)
+	// ListTags 获取仓库的所有 Tag
+	ListTags(repo string) ([]string, error)
+	// FileHash 获取仓库中文件的 git blob Hash
+	FileHash(repo, path string) (string, error)
+	// RawFileUrl 获取仓库中文件的原始文件下载地址
+	RawFileUrl(repo, path string) string
+	// ReleaseDownloadUrl 获取 Release 文件的下载地址
+	ReleaseDownloadUrl(repo, tag, file string) string
+	// CloneUrl 获取仓库的克隆地址
+	CloneUrl(repo string) string
+}
+
+// NewForge 根据镜像类型创建代码托管平台
+//
+// 参数：
+//   - mirror: 镜像配置
+//
+// 返回：
+//   - 代码托管平台
+//   - 错误信息
+func NewForge(mirror MirrorConfig) (Forge, error) {
+	switch strings.ToLower(mirror.Kind) {
+	case MirrorKindGithub:
+		return &githubForge{mirror: mirror}, nil
+	case MirrorKindGitea, MirrorKindForgejo:
+		return &giteaForge{githubForge{mirror: mirror}}, nil
+	case MirrorKindGitlab:
+		return &gitlabForge{mirror: mirror}, nil
+	default:
+		return nil, fmt.Errorf("Unsupported mirror kind '%s': only '%s', '%s', '%s' and '%s' are supported", mirror.Kind, MirrorKindGithub, MirrorKindGitea, MirrorKindForgejo, MirrorKindGitlab)
+	}
+}
+
+// TryForges 按顺序对各镜像的代码托管平台执行操作，直到有一个成功
+//
+// 参数：
+//   - mirrors: 镜像列表
+//   - action: 对单个代码托管平台执行的操作
+//
+// 返回：
+//   - 成功的代码托管平台
+//   - 错误信息，所有镜像都失败时包含每个镜像的错误
+func TryForges(mirrors []MirrorConfig, action func(forge Forge) error) (Forge, error) {
+	if len(mirrors) == 0 {
+		return nil, fmt.Errorf("No mirror configured in [[program.mirrors]]")
+	}
+
+	failures := make([]string, 0, len(mirrors))
+	for _, mirror := range mirrors {
+		forge, err := NewForge(mirror)
+		if err == nil {
+			if err = action(forge); err == nil {
+				return forge, nil
+			}
+		}
+		failures = append(failures, color.Sprintf("%s: %s", mirror.Label(), err))
+	}
+
+	return nil, fmt.Errorf("All mirrors failed (%s)", strings.Join(failures, "; "))
+}
+
+// FindTag 检查 Tag 列表中是否存在指定 Tag
+//
+// 参数：
+//   - tags: Tag 列表
+//   - tag: 指定 Tag
+//
+// 返回：
+//   - 指定 Tag
+//   - 错误信息
+func FindTag(tags []string, tag string) (string, error) {
+	for _, name := range tags {
+		if name == tag {
+			return tag, nil
+		}
+	}
+	return "", fmt.Errorf("Tag %s not found", tag)
+}
+
+// requestJson 请求 API 并将 JSON 格式的响应数据解码到 v
+//
+// 参数：
+//   - url: API 地址
+//   - v: 解码目标
+//
+// 返回：
+//   - 错误信息
+func requestJson(url string, v any) error {
+	body, err := RequestApi(url)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("Response body has unknown structure: %s", err)
+	}
+	return nil
+}
+
+// requestJsonPages 请求分页的 API，按响应头中下一页的地址依次请求，直到最后一页
+//
+//   - 每一页都是 JSON 数组，所有页的元素按顺序合并
+//
+// 参数：
+//   - url: 第一页的 API 地址
+//
+// 返回：
+//   - 所有页的元素
+//   - 错误信息
+func requestJsonPages[T any](url string) ([]T, error) {
+	items := make([]T, 0)
+	visited := make(map[string]bool)
+	for next := url; next != ""; {
+		if visited[next] {
+			break
+		}
+		if len(visited) >= apiMaxPages {
+			return nil, fmt.Errorf("More than %d pages returned by %s", apiMaxPages, url)
+		}
+		visited[next] = true
+
+		body, nextUrl, err := requestApi(next, rateLimitRetries)
+		if err != nil {
+			return nil, err
+		}
+		var page []T
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("Response body has unknown structure: %s", err)
+		}
+		items = append(items, page...)
+		next = nextUrl
+	}
+	return items, nil
+}
@@ -0,0 +1,181 @@
+/*
+File: define_forge_github.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-18 01:42:18
+
+Description: GitHub 和 Gitea/Forgejo 的代码托管平台实现，两者的 API 结构基本一致
+*/
+
+package general
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/gookit/color"
+)
+
+// githubRelease GitHub/Gitea Release API 的响应数据
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	Draft   bool   `json:"draft"`
+	Assets  []struct {
+		Name               string  `json:"name"`
+		Size               float64 `json:"size"`
+		ContentType        string  `json:"content_type"` // Gitea 没有该字段
+		BrowserDownloadUrl string  `json:"browser_download_url"`
+		DownloadCount      float64 `json:"download_count"`
+	} `json:"assets"`
+}
+
+// githubForge GitHub
+type githubForge struct {
+	mirror MirrorConfig // 镜像配置
+}
+
+// Mirror 获取该平台对应的镜像配置
+func (f *githubForge) Mirror() MirrorConfig {
+	return f.mirror
+}
+
+// LatestRelease 获取仓库最新的 Release
+//
+//   - 请求的是 {API}/repos/{OWNER}/{REPO}/releases/latest
+func (f *githubForge) LatestRelease(repo string) (Release, error) {
+	return f.release(color.Sprintf(GoLatestReleaseTagApiFormat, f.mirror.Api, f.mirror.Username, repo))
+}
+
+// ReleaseByTag 获取仓库指定 Tag 的 Release
+//
+//   - 请求的是 {API}/repos/{OWNER}/{REPO}/releases/tags/{TAG}
+func (f *githubForge) ReleaseByTag(repo, tag string) (Release, error) {
+	return f.release(color.Sprintf(GoReleaseTagApiFormat, f.mirror.Api, f.mirror.Username, repo, url.PathEscape(tag)))
+}
+
+// release 请求并解析 Release API
+//
+// 参数：
+//   - api: Release API 地址
+//
+// 返回：
+//   - Release 信息
+//   - 错误信息
+func (f *githubForge) release(api string) (Release, error) {
+	var data githubRelease
+	if err := requestJson(api, &data); err != nil {
+		return Release{}, err
+	}
+	if data.TagName == "" {
+		return Release{}, fmt.Errorf("Response body is empty")
+	}
+	return data.toRelease(), nil
+}
+
+// ListReleases 获取仓库的所有 Release，按页请求直到最后一页（包括先行版本，不包括草稿）
+//
+//   - 请求的是 {API}/repos/{OWNER}/{REPO}/releases
+func (f *githubForge) ListReleases(repo string) ([]Release, error) {
+	data, err := requestJsonPages[githubRelease](color.Sprintf(GoReleasesApiFormat, f.mirror.Api, f.mirror.Username, repo))
+	if err != nil {
+		return nil, err
+	}
+
+	releases := make([]Release, 0, len(data))
+	for _, release := range data {
+		if !release.Draft && release.TagName != "" {
+			releases = append(releases, release.toRelease())
+		}
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("Response body is empty")
+	}
+	return releases, nil
+}
+
+// toRelease 转换为与代码托管平台无关的 Release 信息
+//
+// 返回：
+//   - Release 信息
+func (r githubRelease) toRelease() Release {
+	release := Release{Tag: r.TagName}
+	for _, asset := range r.Assets {
+		release.Assets = append(release.Assets, ReleaseAsset{
+			Name:          asset.Name,
+			Size:          asset.Size,
+			ContentType:   asset.ContentType,
+			DownloadUrl:   asset.BrowserDownloadUrl,
+			DownloadCount: asset.DownloadCount,
+		})
+	}
+	return release
+}
+
+// ListTags 获取仓库的所有 Tag，按页请求直到最后一页
+//
+//   - 请求的是 {API}/repos/{OWNER}/{REPO}/tags
+func (f *githubForge) ListTags(repo string) ([]string, error) {
+	data, err := requestJsonPages[struct {
+		Name string `json:"name"`
+	}](color.Sprintf(GoLatestSourceTagApiFormat, f.mirror.Api, f.mirror.Username, repo))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("Response body is empty")
+	}
+
+	tags := make([]string, 0, len(data))
+	for _, tag := range data {
+		tags = append(tags, tag.Name)
+	}
+	return tags, nil
+}
+
+// FileHash 获取仓库中文件的 git blob Hash
+//
+//   - 请求的是 {API}/repos/{OWNER}/{REPO}/contents/{PATH}，设置了分支时请求该分支的文件
+func (f *githubForge) FileHash(repo, path string) (string, error) {
+	api := color.Sprintf(ShellLatestHashApiFormat, f.mirror.Api, f.mirror.Username, repo, path)
+	if f.mirror.Branch != "" {
+		api = color.Sprintf("%s?ref=%s", api, url.QueryEscape(f.mirror.Branch))
+	}
+
+	var data struct {
+		Sha string `json:"sha"`
+	}
+	if err := requestJson(api, &data); err != nil {
+		return "", err
+	}
+	if data.Sha == "" {
+		return "", fmt.Errorf("Response body is empty")
+	}
+	return data.Sha, nil
+}
+
+// RawFileUrl 获取仓库中文件的原始文件下载地址
+func (f *githubForge) RawFileUrl(repo, path string) string {
+	baseUrl := color.Sprintf(ShellGithubBaseDownloadUrlFormat, f.mirror.Raw, f.mirror.Username, repo, f.mirror.Branch)
+	return f.mirror.Rewrite(color.Sprintf("%s/%s", baseUrl, path))
+}
+
+// ReleaseDownloadUrl 获取 Release 文件的下载地址
+func (f *githubForge) ReleaseDownloadUrl(repo, tag, file string) string {
+	return f.mirror.Rewrite(color.Sprintf(ReleaseDownloadUrlFormat, f.mirror.Url, f.mirror.Username, repo, tag, file))
+}
+
+// CloneUrl 获取仓库的克隆地址
+func (f *githubForge) CloneUrl(repo string) string {
+	return f.mirror.Rewrite(color.Sprintf("%s/%s/%s", f.mirror.Url, f.mirror.Username, repo))
+}
+
+// giteaForge Gitea/Forgejo，只有原始文件地址与 GitHub 不同
+type giteaForge struct {
+	githubForge
+}
+
+// RawFileUrl 获取仓库中文件的原始文件下载地址
+func (f *giteaForge) RawFileUrl(repo, path string) string {
+	baseUrl := color.Sprintf(ShellGiteaBaseDownloadUrlFormat, f.mirror.Raw, f.mirror.Username, repo, f.mirror.Branch)
+	return f.mirror.Rewrite(color.Sprintf("%s/%s", baseUrl, path))
+}
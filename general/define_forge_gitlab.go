@@ -0,0 +1,187 @@
+/*
+File: define_forge_gitlab.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-18 01:42:18
+
+Description: GitLab 的代码托管平台实现
+*/
+
+package general
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+// gitlabRelease GitLab Release API 的响应数据
+type gitlabRelease struct {
+	TagName         string `json:"tag_name"`
+	UpcomingRelease bool   `json:"upcoming_release"`
+	Assets          struct {
+		Links []struct {
+			Name           string `json:"name"`
+			Url            string `json:"url"`
+			DirectAssetUrl string `json:"direct_asset_url"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+// gitlabForge GitLab，仓库所有者可以是带子组的路径，例如 group/subgroup
+type gitlabForge struct {
+	mirror MirrorConfig // 镜像配置
+}
+
+// Mirror 获取该平台对应的镜像配置
+func (f *gitlabForge) Mirror() MirrorConfig {
+	return f.mirror
+}
+
+// project 获取 API 中使用的项目 ID（URL 编码后的 {OWNER}/{REPO}）
+//
+// 参数：
+//   - repo: 仓库名
+//
+// 返回：
+//   - 项目 ID
+func (f *gitlabForge) project(repo string) string {
+	return url.QueryEscape(f.mirror.Username + "/" + repo)
+}
+
+// branch 获取分支名，未设置时使用默认分支
+//
+// 返回：
+//   - 分支名
+func (f *gitlabForge) branch() string {
+	return firstNonEmpty(f.mirror.Branch, "HEAD")
+}
+
+// LatestRelease 获取仓库最新的 Release
+//
+//   - 请求的是 {API}/projects/{ID}/releases/permalink/latest
+func (f *gitlabForge) LatestRelease(repo string) (Release, error) {
+	return f.release(color.Sprintf(GitlabLatestReleaseApiFormat, f.mirror.Api, f.project(repo)))
+}
+
+// ReleaseByTag 获取仓库指定 Tag 的 Release
+//
+//   - 请求的是 {API}/projects/{ID}/releases/{TAG}
+func (f *gitlabForge) ReleaseByTag(repo, tag string) (Release, error) {
+	return f.release(color.Sprintf(GitlabReleaseTagApiFormat, f.mirror.Api, f.project(repo), url.PathEscape(tag)))
+}
+
+// release 请求并解析 Release API
+//
+// 参数：
+//   - api: Release API 地址
+//
+// 返回：
+//   - Release 信息
+//   - 错误信息
+func (f *gitlabForge) release(api string) (Release, error) {
+	var data gitlabRelease
+	if err := requestJson(api, &data); err != nil {
+		return Release{}, err
+	}
+	if data.TagName == "" {
+		return Release{}, fmt.Errorf("Response body is empty")
+	}
+	return data.toRelease(), nil
+}
+
+// ListReleases 获取仓库的所有 Release，按页请求直到最后一页（包括先行版本，不包括尚未发布的 Release）
+//
+//   - 请求的是 {API}/projects/{ID}/releases
+func (f *gitlabForge) ListReleases(repo string) ([]Release, error) {
+	data, err := requestJsonPages[gitlabRelease](color.Sprintf(GitlabReleasesApiFormat, f.mirror.Api, f.project(repo)))
+	if err != nil {
+		return nil, err
+	}
+
+	releases := make([]Release, 0, len(data))
+	for _, release := range data {
+		if !release.UpcomingRelease && release.TagName != "" {
+			releases = append(releases, release.toRelease())
+		}
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("Response body is empty")
+	}
+	return releases, nil
+}
+
+// toRelease 转换为与代码托管平台无关的 Release 信息
+//
+//   - GitLab 的 Release 文件是链接，没有文件大小和下载次数
+//
+// 返回：
+//   - Release 信息
+func (r gitlabRelease) toRelease() Release {
+	release := Release{Tag: r.TagName}
+	for _, link := range r.Assets.Links {
+		release.Assets = append(release.Assets, ReleaseAsset{
+			Name:        link.Name,
+			DownloadUrl: firstNonEmpty(link.DirectAssetUrl, link.Url),
+		})
+	}
+	return release
+}
+
+// ListTags 获取仓库的所有 Tag，按页请求直到最后一页
+//
+//   - 请求的是 {API}/projects/{ID}/repository/tags
+func (f *gitlabForge) ListTags(repo string) ([]string, error) {
+	data, err := requestJsonPages[struct {
+		Name string `json:"name"`
+	}](color.Sprintf(GitlabTagsApiFormat, f.mirror.Api, f.project(repo)))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("Response body is empty")
+	}
+
+	tags := make([]string, 0, len(data))
+	for _, tag := range data {
+		tags = append(tags, tag.Name)
+	}
+	return tags, nil
+}
+
+// FileHash 获取仓库中文件的 git blob Hash
+//
+//   - 请求的是 {API}/projects/{ID}/repository/files/{PATH}?ref={BRANCH}，使用其中的 blob_id
+func (f *gitlabForge) FileHash(repo, path string) (string, error) {
+	api := color.Sprintf(GitlabFileApiFormat, f.mirror.Api, f.project(repo), strings.ReplaceAll(url.PathEscape(path), "/", "%2F"), url.QueryEscape(f.branch()))
+
+	var data struct {
+		BlobId string `json:"blob_id"`
+	}
+	if err := requestJson(api, &data); err != nil {
+		return "", err
+	}
+	if data.BlobId == "" {
+		return "", fmt.Errorf("Response body is empty")
+	}
+	return data.BlobId, nil
+}
+
+// RawFileUrl 获取仓库中文件的原始文件下载地址
+func (f *gitlabForge) RawFileUrl(repo, path string) string {
+	return f.mirror.Rewrite(color.Sprintf(GitlabRawFileUrlFormat, firstNonEmpty(f.mirror.Raw, f.mirror.Url), f.mirror.Username, repo, f.branch(), path))
+}
+
+// ReleaseDownloadUrl 获取 Release 文件的下载地址
+//
+//   - 该地址要求 Release 链接的 filepath 为 /{FILE}，否则应使用 Release 信息中的下载地址
+func (f *gitlabForge) ReleaseDownloadUrl(repo, tag, file string) string {
+	return f.mirror.Rewrite(color.Sprintf(GitlabReleaseDownloadUrlFormat, f.mirror.Url, f.mirror.Username, repo, tag, file))
+}
+
+// CloneUrl 获取仓库的克隆地址
+func (f *gitlabForge) CloneUrl(repo string) string {
+	return f.mirror.Rewrite(color.Sprintf("%s/%s/%s.git", f.mirror.Url, f.mirror.Username, repo))
+}
@@ -10,27 +10,150 @@ Description: git 操作
 package general
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 )
 
 // CloneRepoViaHTTP 通过 HTTP 协议克隆仓库
 //
+//   - 指定 Tag 时只浅克隆该 Tag，保证构建的代码与 Tag 一致
+//   - 克隆失败时删除不完整的本地仓库，以便从其他镜像重新克隆
+//
 // 参数：
 //   - path: 本地仓库存储路径
-//   - url: 远程仓库地址（不包括仓库名，https://github.com/{UserName}）
-//   - repo: 仓库名
+//   - url: 远程仓库克隆地址（https://github.com/{UserName}/{Repo}）
+//   - repo: 仓库名，用作本地仓库文件夹名
+//   - tag: 要检出的 Tag，为空时检出默认分支
 //
 // 返回：
 //   - 错误信息
-func CloneRepoViaHTTP(path string, url string, repo string) error {
-	_, err := git.PlainClone(filepath.Join(path, repo), false, &git.CloneOptions{
-		URL:               url + "/" + repo,
+func CloneRepoViaHTTP(path string, url string, repo string, tag string) error {
+	options := &git.CloneOptions{
+		URL:               url,
 		RecurseSubmodules: 1,
-	})
+	}
+	if tag != "" {
+		options.ReferenceName = plumbing.NewTagReferenceName(tag)
+		options.SingleBranch = true
+		options.Depth = 1
+		options.Tags = git.NoTags
+	}
+	repoPath := filepath.Join(path, repo)
+	if _, err := git.PlainClone(repoPath, false, options); err != nil {
+		os.RemoveAll(repoPath)
+		return err
+	}
+	return nil
+}
+
+// IsGitRepo 检查路径是否为可以打开的 git 仓库
+//
+// 参数：
+//   - path: 本地仓库路径
+//
+// 返回：
+//   - 是否为 git 仓库
+func IsGitRepo(path string) bool {
+	_, err := git.PlainOpen(path)
+	return err == nil
+}
+
+// SyncRepoViaHTTP 将本地源码缓存同步到指定 Tag
+//
+//   - 缓存不存在时浅克隆该 Tag
+//   - 缓存存在时只拉取该 Tag 并检出，同时清理上次构建留下的未跟踪文件
+//   - 缓存损坏（无法打开或检出）时删除并重新克隆；拉取失败（例如网络错误）时保留缓存并返回错误
+//
+// 参数：
+//   - path: 本地仓库存储路径
+//   - url: 远程仓库克隆地址
+//   - repo: 仓库名，用作本地仓库文件夹名
+//   - tag: 要检出的 Tag
+//
+// 返回：
+//   - 错误信息
+func SyncRepoViaHTTP(path string, url string, repo string, tag string) error {
+	repoPath := filepath.Join(path, repo)
+	if !FileExist(repoPath) {
+		return CloneRepoViaHTTP(path, url, repo, tag)
+	}
+
+	// 打开缓存的仓库，无法打开说明缓存已损坏
+	repository, err := git.PlainOpen(repoPath)
 	if err != nil {
+		return recloneRepo(path, url, repo, tag, err)
+	}
+
+	// 只拉取指定 Tag
+	tagRef := plumbing.NewTagReferenceName(tag)
+	err = repository.Fetch(&git.FetchOptions{
+		RemoteURL: url,
+		RefSpecs:  []gitconfig.RefSpec{gitconfig.RefSpec(fmt.Sprintf("+%s:%s", tagRef, tagRef))},
+		Depth:     1,
+		Tags:      git.NoTags,
+		Force:     true,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
 		return err
 	}
+
+	// 检出指定 Tag，检出失败说明缓存已损坏
+	if err := checkoutTag(repository, tag); err != nil {
+		return recloneRepo(path, url, repo, tag, err)
+	}
 	return nil
 }
+
+// checkoutTag 强制检出仓库的指定 Tag 并更新子模块
+//
+// 参数：
+//   - repository: 本地仓库
+//   - tag: 要检出的 Tag
+//
+// 返回：
+//   - 错误信息
+func checkoutTag(repository *git.Repository, tag string) error {
+	hash, err := repository.ResolveRevision(plumbing.Revision(plumbing.NewTagReferenceName(tag)))
+	if err != nil {
+		return err
+	}
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return err
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: *hash, Force: true}); err != nil {
+		return err
+	}
+	if err := worktree.Clean(&git.CleanOptions{Dir: true}); err != nil {
+		return err
+	}
+
+	submodules, err := worktree.Submodules()
+	if err != nil {
+		return err
+	}
+	return submodules.Update(&git.SubmoduleUpdateOptions{Init: true, RecurseSubmodules: 1})
+}
+
+// recloneRepo 删除已损坏的本地仓库并重新克隆
+//
+// 参数：
+//   - path: 本地仓库存储路径
+//   - url: 远程仓库克隆地址
+//   - repo: 仓库名
+//   - tag: 要检出的 Tag
+//   - cause: 判断仓库已损坏的原因
+//
+// 返回：
+//   - 错误信息
+func recloneRepo(path string, url string, repo string, tag string, cause error) error {
+	if err := os.RemoveAll(filepath.Join(path, repo)); err != nil {
+		return fmt.Errorf("Source cache is corrupt (%s) and could not be removed: %s", cause, err)
+	}
+	return CloneRepoViaHTTP(path, url, repo, tag)
+}
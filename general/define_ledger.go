@@ -0,0 +1,210 @@
+/*
+File: define_ledger.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-17 22:31:09
+
+Description: 结构化记账文件，记录程序的版本、来源和拥有的文件
+*/
+
+package general
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gookit/color"
+	"github.com/pelletier/go-toml"
+)
+
+// Ledger 单个程序的记账信息
+type Ledger struct {
+	Name        string       `toml:"name"`         // 程序名
+	Version     string       `toml:"version"`      // 已安装版本（shell 脚本为 Hash）
+	Method      string       `toml:"method"`       // 安装方式，release、source 或 script
+	Source      string       `toml:"source"`       // 来源地址，release 为压缩包下载地址，source 为克隆地址，script 为脚本下载地址
+	InstallTime time.Time    `toml:"install_time"` // 安装时间
+	Files       []LedgerFile `toml:"files"`        // 程序拥有的文件
+}
+
+// LedgerFile 记账文件中记录的单个文件
+type LedgerFile struct {
+	Path   string `toml:"path"`   // 文件路径
+	SHA256 string `toml:"sha256"` // 安装时的 SHA-256 校验和
+	Mode   string `toml:"mode"`   // 安装时的权限，八进制
+}
+
+// NewLedger 创建一个新的记账信息，安装时间为当前时间
+//
+// 参数：
+//   - name: 程序名
+//   - version: 已安装版本
+//   - method: 安装方式
+//   - source: 来源地址
+//
+// 返回：
+//   - 记账信息
+func NewLedger(name, version, method, source string) *Ledger {
+	return &Ledger{
+		Name:        name,
+		Version:     version,
+		Method:      method,
+		Source:      source,
+		InstallTime: time.Now().Truncate(time.Second),
+		Files:       make([]LedgerFile, 0),
+	}
+}
+
+// AddFile 记录文件及其当前的校验和与权限，已记录的文件会被更新
+//
+// 参数：
+//   - path: 文件路径
+//
+// 返回：
+//   - 错误信息
+func (l *Ledger) AddFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	checksum, err := FileSHA256(path)
+	if err != nil {
+		return err
+	}
+	file := LedgerFile{
+		Path:   path,
+		SHA256: checksum,
+		Mode:   FormatFileMode(info.Mode()),
+	}
+
+	for index := range l.Files {
+		if l.Files[index].Path == path {
+			l.Files[index] = file
+			return nil
+		}
+	}
+	l.Files = append(l.Files, file)
+
+	return nil
+}
+
+// Paths 获取记录的所有文件路径
+//
+// 返回：
+//   - 文件路径
+func (l *Ledger) Paths() []string {
+	paths := make([]string, 0, len(l.Files))
+	for _, file := range l.Files {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
+
+// HasBaseline 文件是否有安装时记录的校验和，旧的纯文本记账文件转换得到的文件没有
+//
+// 返回：
+//   - 是否有校验和
+func (f LedgerFile) HasBaseline() bool {
+	return f.SHA256 != ""
+}
+
+// ReadLedger 读取记账文件，不修改记账文件
+//
+//   - 旧的纯文本记账文件（每行一个文件路径）只转换路径，不记录校验和和权限，重新安装时才会写入新格式
+//
+// 参数：
+//   - pocketFile: 记账文件路径
+//
+// 返回：
+//   - 记账信息
+//   - 错误信息
+func ReadLedger(pocketFile string) (*Ledger, error) {
+	content, err := os.ReadFile(pocketFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// 新格式
+	if tree, err := toml.LoadBytes(content); err == nil && (tree.Has("name") || tree.Has("files") || len(bytes.TrimSpace(content)) == 0) {
+		ledger := &Ledger{}
+		if err := tree.Unmarshal(ledger); err != nil {
+			return nil, err
+		}
+		if ledger.Name == "" {
+			ledger.Name = filepath.Base(filepath.Dir(pocketFile))
+		}
+		return ledger, nil
+	}
+
+	// 旧格式，文件当前的内容未必是安装时的内容，不能作为校验基准
+	ledger := NewLedger(filepath.Base(filepath.Dir(pocketFile)), "", "", "")
+	if info, err := os.Stat(pocketFile); err == nil {
+		ledger.InstallTime = info.ModTime().Truncate(time.Second)
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		path := strings.TrimSpace(line)
+		if path == "" {
+			continue
+		}
+		ledger.Files = append(ledger.Files, LedgerFile{Path: path})
+	}
+
+	return ledger, nil
+}
+
+// WriteLedger 写入记账文件，先写入临时文件再替换，避免记账文件只写入一部分
+//
+// 参数：
+//   - pocketFile: 记账文件路径
+//   - ledger: 记账信息
+//
+// 返回：
+//   - 错误信息
+func WriteLedger(pocketFile string, ledger *Ledger) error {
+	buffer := new(bytes.Buffer)
+	encoder := toml.NewEncoder(buffer)
+	encoder.Order(toml.OrderPreserve)
+	if err := encoder.Encode(ledger); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(pocketFile), 0755); err != nil {
+		return err
+	}
+	tempFile := color.Sprintf("%s.tmp", pocketFile)
+	if err := os.WriteFile(tempFile, buffer.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempFile, pocketFile)
+}
+
+// FormatFileMode 将文件权限格式化为八进制字符串
+//
+// 参数：
+//   - mode: 文件权限
+//
+// 返回：
+//   - 八进制权限，例如 0755
+func FormatFileMode(mode os.FileMode) string {
+	return color.Sprintf("%04o", mode.Perm())
+}
+
+// ParseFileMode 解析八进制权限字符串
+//
+// 参数：
+//   - mode: 八进制权限，例如 0755
+//
+// 返回：
+//   - 文件权限
+//   - 错误信息
+func ParseFileMode(mode string) (os.FileMode, error) {
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return 0, err
+	}
+	return os.FileMode(perm).Perm(), nil
+}
@@ -10,12 +10,31 @@ Description: 安装/卸载管理
 package general
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gookit/color"
+)
+
+// 历史版本相关的文件名
+var (
+	historyDir          = "history"  // 历史版本存储目录
+	historyVersionFile  = "version"  // 历史版本的版本号文件
+	historyManifestFile = "manifest" // 历史版本的文件清单，按行与 content 目录中的文件一一对应
+	historyContentDir   = "content"  // 历史版本的文件副本目录
 )
 
 // Install 安装，覆盖已存在的同名文件
 //
+// 先写入目标文件夹中的临时文件，落盘并设置权限后再重命名为目标文件，保证目标文件要么是旧文件，要么是完整的新文件
+//
 // 参数：
 //   - sourceFile: 源文件路径
 //   - targetFile: 目标文件路径
@@ -23,7 +42,7 @@ import (
 //
 // 返回：
 //   - 错误信息
-func Install(sourceFile, targetFile string, perm os.FileMode) error {
+func Install(sourceFile, targetFile string, perm os.FileMode) (err error) {
 	// 打开源文件
 	sFile, err := os.Open(sourceFile)
 	if err != nil {
@@ -31,17 +50,48 @@ func Install(sourceFile, targetFile string, perm os.FileMode) error {
 	}
 	defer sFile.Close()
 
-	// 创建或打开目标文件
-	tFile, err := os.OpenFile(targetFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, perm)
+	// 在目标文件夹中创建临时文件，保证重命名时不跨文件系统
+	targetDir := filepath.Dir(targetFile)
+	tFile, err := os.CreateTemp(targetDir, color.Sprintf(".%s.*.tmp", filepath.Base(targetFile)))
 	if err != nil {
 		return err
 	}
-	defer tFile.Close()
+	tempFile := tFile.Name()
+	defer func() {
+		// 出错时清理临时文件
+		if err != nil {
+			tFile.Close()
+			os.Remove(tempFile)
+		}
+	}()
 
-	// 复制文件内容
+	// 复制文件内容并落盘
 	if _, err = io.Copy(tFile, sFile); err != nil {
 		return err
 	}
+	if err = tFile.Sync(); err != nil {
+		return err
+	}
+	if err = tFile.Close(); err != nil {
+		return err
+	}
+
+	// 设置权限
+	if err = os.Chmod(tempFile, perm); err != nil {
+		return err
+	}
+
+	// 替换目标文件，正在运行的程序不受影响
+	if err = os.Rename(tempFile, targetFile); err != nil {
+		return err
+	}
+
+	// 落盘目标文件夹，使重命名持久化（部分平台不支持，忽略错误）
+	if dir, dirErr := os.Open(targetDir); dirErr == nil {
+		dir.Sync()
+		dir.Close()
+	}
+
 	return nil
 }
 
@@ -56,16 +106,247 @@ func Uninstall(targetFile string) error {
 	return DeleteFile(targetFile)
 }
 
-// InitPocketFile 初始化记账文件
+// BackupVersion 在更新事务中备份程序当前版本的文件和记账文件
+//
+//   - 历史版本目录记录在事务中，事务撤销时一并删除；清理超出保留数量的历史版本见 PruneBackupVersions
+//
+// 参数：
+//   - transaction: 更新事务
+//   - pocketFile: 记账文件路径，历史版本存储在其所在目录的 history 文件夹中
+//   - mainFile: 程序主文件路径，记账文件中没有记录时也会备份
+//   - version: 当前版本
+//   - retain: 保留的历史版本数，小于等于 0 时不备份
+//
+// 返回：
+//   - 错误信息
+func BackupVersion(transaction *Transaction, pocketFile, mainFile, version string, retain int) error {
+	if retain <= 0 {
+		return nil
+	}
+
+	// 需要备份的文件
+	files := make([]string, 0)
+	if FileExist(pocketFile) {
+		ledger, err := ReadLedger(pocketFile)
+		if err != nil {
+			return err
+		}
+		for _, line := range ledger.Paths() {
+			if line != "" && FileExist(line) && !slices.Contains(files, line) {
+				files = append(files, line)
+			}
+		}
+	}
+	if FileExist(mainFile) && !slices.Contains(files, mainFile) {
+		files = append(files, mainFile)
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	// 创建历史版本目录，以时间戳开头便于排序
+	pocketDir := filepath.Dir(pocketFile)
+	backupName := color.Sprintf("%d_%s", time.Now().UnixNano(), filepath.Base(version))
+	backupDir := filepath.Join(pocketDir, historyDir, backupName)
+	if err := transaction.Track(backupDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(backupDir, historyContentDir), 0755); err != nil {
+		return err
+	}
+
+	// 备份文件
+	for index, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if err := Install(file, filepath.Join(backupDir, historyContentDir, strconv.Itoa(index)), info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if err := WriteFile(filepath.Join(backupDir, historyManifestFile), strings.Join(files, "\n")+"\n", "t"); err != nil {
+		return err
+	}
+	if err := WriteFile(filepath.Join(backupDir, historyVersionFile), version, "t"); err != nil {
+		return err
+	}
+	if FileExist(pocketFile) {
+		if err := Install(pocketFile, filepath.Join(backupDir, filepath.Base(pocketFile)), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PruneBackupVersions 清理超出保留数量的历史版本，在更新事务提交后调用
 //
 // 参数：
 //   - pocketFile: 记账文件路径
+//   - retain: 保留的历史版本数，小于等于 0 时不清理
 //
 // 返回：
 //   - 错误信息
-func InitPocketFile(pocketFile string) error {
-	if err := CreateFile(pocketFile); err != nil {
+func PruneBackupVersions(pocketFile string, retain int) error {
+	if retain <= 0 {
+		return nil
+	}
+
+	pocketDir := filepath.Dir(pocketFile)
+	backups, err := ListBackupVersions(pocketFile)
+	if err != nil {
 		return err
 	}
-	return EmptyFile(pocketFile)
+	for index, backup := range backups {
+		if index >= retain {
+			if err := DeleteFile(filepath.Join(pocketDir, historyDir, backup[0])); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ListBackupVersions 列出程序的历史版本，最新的在前
+//
+// 参数：
+//   - pocketFile: 记账文件路径
+//
+// 返回：
+//   - 历史版本列表，每项为 [历史版本目录名, 版本号]
+//   - 错误信息
+func ListBackupVersions(pocketFile string) ([][2]string, error) {
+	backups := make([][2]string, 0)
+
+	backupRoot := filepath.Join(filepath.Dir(pocketFile), historyDir)
+	if !FileExist(backupRoot) {
+		return backups, nil
+	}
+	entries, err := os.ReadDir(backupRoot)
+	if err != nil {
+		return backups, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		version, err := os.ReadFile(filepath.Join(backupRoot, entry.Name(), historyVersionFile))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, [2]string{entry.Name(), string(version)})
+	}
+	// 目录名以时间戳开头，倒序即最新的在前
+	sort.Slice(backups, func(i, j int) bool {
+		iStamp, _ := strconv.ParseInt(strings.SplitN(backups[i][0], "_", 2)[0], 10, 64)
+		jStamp, _ := strconv.ParseInt(strings.SplitN(backups[j][0], "_", 2)[0], 10, 64)
+		return iStamp > jStamp
+	})
+
+	return backups, nil
+}
+
+// RestoreVersion 在事务中恢复程序的历史版本，包括文件和记账文件
+//
+//   - 删除只存在于当前版本中的文件
+//   - 任一步骤失败时撤销本次恢复，历史版本保留以便重试；事务提交后才删除该历史版本
+//
+// 参数：
+//   - pocketPath: 记账文件夹路径
+//   - program: 程序名
+//   - pocketFile: 记账文件路径
+//   - backup: 历史版本目录名
+//
+// 返回：
+//   - 错误信息
+func RestoreVersion(pocketPath, program, pocketFile, backup string) error {
+	backupDir := filepath.Join(filepath.Dir(pocketFile), historyDir, backup)
+	if !FileExist(backupDir) {
+		return fmt.Errorf("Backup %s does not exist", backup)
+	}
+
+	// 读取文件清单
+	files, err := ReadFile(filepath.Join(backupDir, historyManifestFile))
+	if err != nil {
+		return err
+	}
+
+	// 当前版本的文件
+	currentFiles := make([]string, 0)
+	if FileExist(pocketFile) {
+		ledger, err := ReadLedger(pocketFile)
+		if err != nil {
+			return err
+		}
+		currentFiles = ledger.Paths()
+	}
+
+	// 开始恢复事务，记账文件会被覆盖
+	transaction, err := BeginTransaction(pocketPath, program, pocketFile)
+	if err != nil {
+		return err
+	}
+	if err := restoreFiles(transaction, backupDir, files, currentFiles, pocketFile); err != nil {
+		if rollbackErr := transaction.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%s (%s)", err, rollbackErr)
+		}
+		return err
+	}
+	if err := transaction.Commit(); err != nil {
+		return err
+	}
+
+	return DeleteFile(backupDir)
+}
+
+// restoreFiles 在事务中恢复历史版本的文件和记账文件，并删除只存在于当前版本中的文件
+//
+// 参数：
+//   - transaction: 恢复事务
+//   - backupDir: 历史版本目录
+//   - files: 历史版本的文件清单
+//   - currentFiles: 当前版本的文件
+//   - pocketFile: 记账文件路径
+//
+// 返回：
+//   - 错误信息
+func restoreFiles(transaction *Transaction, backupDir string, files, currentFiles []string, pocketFile string) error {
+	// 删除只存在于当前版本中的文件
+	for _, file := range currentFiles {
+		if file != "" && !slices.Contains(files, file) {
+			if err := transaction.Remove(file); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 恢复文件
+	for index, file := range files {
+		if file == "" {
+			continue
+		}
+		backupFile := filepath.Join(backupDir, historyContentDir, strconv.Itoa(index))
+		info, err := os.Stat(backupFile)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return err
+		}
+		if err := transaction.Install(backupFile, file, info.Mode().Perm()); err != nil {
+			return err
+		}
+		if err := os.Chmod(file, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	// 恢复记账文件
+	backupPocketFile := filepath.Join(backupDir, filepath.Base(pocketFile))
+	if FileExist(backupPocketFile) {
+		return transaction.Install(backupPocketFile, pocketFile, 0644)
+	}
+	return nil
 }
@@ -11,6 +11,7 @@ package general
 
 var (
 	LatestVersionMessage       = "is already the latest version"                   // 输出文本 - 已安装的程序和脚本为最新版
+	NewerThanRemoteMessage     = "is newer than the remote version %s"             // 输出文本 - 已安装的程序比远端版本新
 	UnableToCompileMessage     = "Makefile or main.go file does not exist"         // 输出文本 - 缺失编译文件无法完成编译
 	AcsInstallSuccessMessage   = "auto-completion script installed successfully"   // 输出文本 - 自动补全脚本安装成功
 	AcsInstallFailedMessage    = "auto-completion script installation failed"      // 输出文本 - 自动补全脚本安装失败
@@ -19,11 +20,24 @@ var (
 )
 
 var (
-	GoLatestReleaseTagApiFormat      = "%s/repos/%s/%s/releases/latest" // API 和下载地址 - 请求远端仓库最新 Tag 的 API - Release
-	GoLatestSourceTagApiFormat       = "%s/repos/%s/%s/tags"            // API 和下载地址 - 请求远端仓库最新 Tag 的 API - Source
-	ShellLatestHashApiFormat         = "%s/repos/%s/%s/contents/%s/%s"  // API 和下载地址 - 请求远端仓库最新脚本的 Hash 值的 API
-	ShellGithubBaseDownloadUrlFormat = "%s/%s/%s/%s"                    // API 和下载地址 - 远端仓库脚本基础下载地址（不包括在仓库路中的路径） - GitHub 格式
-	ShellGiteaBaseDownloadUrlFormat  = "%s/%s/%s/raw/branch/%s"         // API 和下载地址 - 远端仓库脚本基础下载地址（不包括在仓库路中的路径） - Gitea 格式
+	GoLatestReleaseTagApiFormat      = "%s/repos/%s/%s/releases/latest"       // API 和下载地址 - 请求远端仓库最新 Tag 的 API - Release
+	GoReleaseTagApiFormat            = "%s/repos/%s/%s/releases/tags/%s"      // API 和下载地址 - 请求远端仓库指定 Tag 的 API - Release
+	GoReleasesApiFormat              = "%s/repos/%s/%s/releases?per_page=100" // API 和下载地址 - 请求远端仓库 Release 列表的 API - Release
+	GoLatestSourceTagApiFormat       = "%s/repos/%s/%s/tags?per_page=100"     // API 和下载地址 - 请求远端仓库最新 Tag 的 API - Source
+	ShellLatestHashApiFormat         = "%s/repos/%s/%s/contents/%s"           // API 和下载地址 - 请求远端仓库最新脚本的 Hash 值的 API
+	ShellGithubBaseDownloadUrlFormat = "%s/%s/%s/%s"                          // API 和下载地址 - 远端仓库脚本基础下载地址（不包括在仓库路中的路径） - GitHub 格式
+	ShellGiteaBaseDownloadUrlFormat  = "%s/%s/%s/raw/branch/%s"               // API 和下载地址 - 远端仓库脚本基础下载地址（不包括在仓库路中的路径） - Gitea 格式
+	ReleaseDownloadUrlFormat         = "%s/%s/%s/releases/download/%s/%s"     // API 和下载地址 - Release 文件下载地址
+)
+
+var (
+	GitlabLatestReleaseApiFormat   = "%s/projects/%s/releases/permalink/latest"    // API 和下载地址 - 请求远端仓库最新 Release 的 API - GitLab 格式
+	GitlabReleaseTagApiFormat      = "%s/projects/%s/releases/%s"                  // API 和下载地址 - 请求远端仓库指定 Tag 的 Release 的 API - GitLab 格式
+	GitlabReleasesApiFormat        = "%s/projects/%s/releases?per_page=100"        // API 和下载地址 - 请求远端仓库 Release 列表的 API - GitLab 格式
+	GitlabTagsApiFormat            = "%s/projects/%s/repository/tags?per_page=100" // API 和下载地址 - 请求远端仓库 Tag 列表的 API - GitLab 格式
+	GitlabFileApiFormat            = "%s/projects/%s/repository/files/%s?ref=%s"   // API 和下载地址 - 请求远端仓库文件信息的 API - GitLab 格式
+	GitlabRawFileUrlFormat         = "%s/%s/%s/-/raw/%s/%s"                        // API 和下载地址 - 远端仓库原始文件下载地址 - GitLab 格式
+	GitlabReleaseDownloadUrlFormat = "%s/%s/%s/-/releases/%s/downloads/%s"         // API 和下载地址 - Release 文件下载地址 - GitLab 格式
 )
 
 var (
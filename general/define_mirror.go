@@ -0,0 +1,101 @@
+/*
+File: define_mirror.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-18 01:05:52
+
+Description: 远端仓库镜像，按配置的顺序依次尝试
+*/
+
+package general
+
+import (
+	"github.com/gookit/color"
+	"github.com/pelletier/go-toml"
+)
+
+// 镜像类型
+const (
+	MirrorKindGithub  = "github"  // GitHub
+	MirrorKindGitea   = "gitea"   // Gitea
+	MirrorKindForgejo = "forgejo" // Forgejo，与 Gitea 使用相同的 API
+	MirrorKindGitlab  = "gitlab"  // GitLab
+)
+
+// Label 获取用于输出的镜像名，未设置名称时使用镜像类型
+//
+// 返回：
+//   - 镜像名
+func (m MirrorConfig) Label() string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return m.Kind
+}
+
+// Rewrite 为下载和克隆地址添加镜像的地址前缀，例如 GitHub 加速代理
+//
+// 参数：
+//   - url: 原地址
+//
+// 返回：
+//   - 添加前缀后的地址
+func (m MirrorConfig) Rewrite(url string) string {
+	if m.UrlPrefix == "" {
+		return url
+	}
+	return m.UrlPrefix + url
+}
+
+// PreferMirror 将指定镜像移到镜像列表最前面，其余镜像保持原顺序
+//
+// 参数：
+//   - mirrors: 镜像列表
+//   - preferred: 优先使用的镜像
+//
+// 返回：
+//   - 调整顺序后的镜像列表
+func PreferMirror(mirrors []MirrorConfig, preferred MirrorConfig) []MirrorConfig {
+	ordered := []MirrorConfig{preferred}
+	for _, mirror := range mirrors {
+		if mirror != preferred {
+			ordered = append(ordered, mirror)
+		}
+	}
+	return ordered
+}
+
+// legacyMirrors 从旧配置文件的 github_* 和 gitea_* 配置项构建镜像列表
+//
+// 参数：
+//   - configTree: 解析 toml 配置文件得到的配置树
+//
+// 返回：
+//   - 镜像列表，旧配置项也不存在时使用默认镜像列表
+func legacyMirrors(configTree *toml.Tree) []MirrorConfig {
+	get := func(key string) string {
+		value, _ := configTree.Get(key).(string)
+		return value
+	}
+
+	mirrors := make([]MirrorConfig, 0, 2)
+	for _, kind := range []string{MirrorKindGithub, MirrorKindGitea} {
+		mirror := MirrorConfig{
+			Name:     kind,
+			Kind:     kind,
+			Api:      firstNonEmpty(get(color.Sprintf("program.go.%s_api", kind)), get(color.Sprintf("program.shell.%s_api", kind))),
+			Url:      get(color.Sprintf("program.go.%s_url", kind)),
+			Raw:      get(color.Sprintf("program.shell.%s_raw", kind)),
+			Username: firstNonEmpty(get(color.Sprintf("program.go.%s_username", kind)), get(color.Sprintf("program.shell.%s_username", kind))),
+			Branch:   get(color.Sprintf("program.shell.%s_branch", kind)),
+		}
+		if mirror.Api != "" || mirror.Url != "" {
+			mirrors = append(mirrors, mirror)
+		}
+	}
+	if len(mirrors) == 0 {
+		return appConfig.Program.Mirrors
+	}
+
+	return mirrors
+}
@@ -45,14 +45,15 @@ var keyMap = map[string]string{
 
 // model 结构体，选择器的数据模型
 type model struct {
-	choices   []string         // 所有选项
-	hlChoices []string         // 高亮选项
-	cursor    int              // 光标当前所在选项的索引
-	selected  map[int]struct{} // 已选中选项，key 为选项 choices 的索引。使用 map 便于判断指定选项是否已被选中
-	negatives string           // 希望选择器在运行后输出的信息
-	ready     bool             // 模型是否准备好
-	viewport  viewport.Model   // 视图窗口
-	builder   strings.Builder  // 用于构建字符串
+	choices   []string          // 所有选项
+	hlChoices []string          // 高亮选项
+	notes     map[string]string // 选项的附加说明，只用于显示
+	cursor    int               // 光标当前所在选项的索引
+	selected  map[int]struct{}  // 已选中选项，key 为选项 choices 的索引。使用 map 便于判断指定选项是否已被选中
+	negatives string            // 希望选择器在运行后输出的信息
+	ready     bool              // 模型是否准备好
+	viewport  viewport.Model    // 视图窗口
+	builder   strings.Builder   // 用于构建字符串
 }
 
 // initialModel 初始化选择器数据模型
@@ -60,11 +61,12 @@ type model struct {
 // 参数：
 //   - choices: 可选项
 //   - highlights: 高亮项
+//   - notes: 选项的附加说明，只用于显示
 //   - negatives: 希望选择器在运行后输出的信息
 //
 // 返回：
 //   - 初始化后的选择器数据模型
-func initialModel(choices, highlights []string, negatives string) *model {
+func initialModel(choices, highlights []string, notes map[string]string, negatives string) *model {
 	allChoices := make([]string, 0)
 	allChoices = append(allChoices, color.Sprintf("%s%s", SelectAllFlag, FgLightYellowText(SelectAllTips)))
 	allChoices = append(allChoices, choices...)
@@ -75,6 +77,7 @@ func initialModel(choices, highlights []string, negatives string) *model {
 	return &model{
 		choices:   allChoices,
 		hlChoices: hlChoices,
+		notes:     notes,
 		cursor:    0,
 		selected:  make(map[int]struct{}),
 		negatives: negatives,
@@ -215,7 +218,11 @@ func (m *model) content() string {
 			if slices.Contains(m.hlChoices, choice) {
 				hiFlag = NiceFlag // 在高亮项中
 			}
-			choice = color.Sprintf("%s %s", hiFlag, choice)
+			if note, ok := m.notes[choice]; ok {
+				choice = color.Sprintf("%s %s %s", hiFlag, choice, SecondaryText(note))
+			} else {
+				choice = color.Sprintf("%s %s", hiFlag, choice)
+			}
 		}
 		// 检查光标是否指向当前选项，默认未指向
 		cursorFlag := CursorOffFlag // 未指向当前选项
@@ -313,14 +320,15 @@ func (m *model) getOptimalViewportHeight(msg tea.WindowSizeMsg) int {
 // 参数：
 //   - choices: 可选项
 //   - highlights: 高亮项
+//   - notes: 选项的附加说明，只用于显示，可以为 nil
 //   - negatives: 希望选择器在运行后输出的信息
 //
 // 返回：
 //   - 已选项
 //   - 错误信息
-func MultipleSelectionFilter(choices, highlights []string, negatives string) ([]string, error) {
+func MultipleSelectionFilter(choices, highlights []string, notes map[string]string, negatives string) ([]string, error) {
 	program := tea.NewProgram(
-		initialModel(choices, highlights, negatives),
+		initialModel(choices, highlights, notes, negatives),
 		tea.WithAltScreen(), // 启动程序时启用备用屏幕缓冲区，即程序以全窗口模式启动
 	)
 
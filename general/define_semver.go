@@ -0,0 +1,200 @@
+/*
+File: define_semver.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-18 03:10:27
+
+Description: 语义化版本的解析和比较
+*/
+
+package general
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SemVer 语义化版本，忽略构建元数据
+type SemVer struct {
+	Major      int      // 主版本号
+	Minor      int      // 次版本号
+	Patch      int      // 修订号
+	Prerelease []string // 先行版本标识，例如 rc.1 为 ["rc", "1"]
+}
+
+// ParseSemVer 解析语义化版本，允许 v 前缀和省略次版本号、修订号（例如 v1.2）
+//
+// 参数：
+//   - version: 版本字符串
+//
+// 返回：
+//   - 语义化版本
+//   - 是否为合法的语义化版本
+func ParseSemVer(version string) (SemVer, bool) {
+	var semver SemVer
+
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	version, _, _ = strings.Cut(version, "+") // 构建元数据不参与比较
+	version, prerelease, hasPrerelease := strings.Cut(version, "-")
+	if hasPrerelease {
+		if prerelease == "" {
+			return semver, false
+		}
+		semver.Prerelease = strings.Split(prerelease, ".")
+		for _, identifier := range semver.Prerelease {
+			if identifier == "" {
+				return semver, false
+			}
+		}
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) > 3 {
+		return semver, false
+	}
+	numbers := []*int{&semver.Major, &semver.Minor, &semver.Patch}
+	for index, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return semver, false
+		}
+		*numbers[index] = number
+	}
+
+	return semver, true
+}
+
+// IsPrerelease 是否为先行版本
+//
+// 返回：
+//   - 是否为先行版本
+func (v SemVer) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Compare 按语义化版本规则与另一个版本比较
+//
+// 参数：
+//   - other: 另一个版本
+//
+// 返回：
+//   - v 较旧时为 -1，相同时为 0，较新时为 1
+func (v SemVer) Compare(other SemVer) int {
+	for _, pair := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if pair[0] != pair[1] {
+			return compareInt(pair[0], pair[1])
+		}
+	}
+
+	// 正式版本比同版本号的先行版本新
+	switch {
+	case !v.IsPrerelease() && !other.IsPrerelease():
+		return 0
+	case !v.IsPrerelease():
+		return 1
+	case !other.IsPrerelease():
+		return -1
+	}
+
+	// 逐个比较先行版本标识，数字标识按数值比较且比字母标识旧
+	for index := 0; index < len(v.Prerelease) && index < len(other.Prerelease); index++ {
+		a, b := v.Prerelease[index], other.Prerelease[index]
+		aNumber, aErr := strconv.Atoi(a)
+		bNumber, bErr := strconv.Atoi(b)
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNumber != bNumber {
+				return compareInt(aNumber, bNumber)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if a != b {
+				return strings.Compare(a, b)
+			}
+		}
+	}
+	return compareInt(len(v.Prerelease), len(other.Prerelease))
+}
+
+// HighestTag 选出 Tag 列表中语义化版本最高的 Tag
+//
+//   - 不是语义化版本的 Tag 被忽略，所有 Tag 都不是语义化版本时使用按名称排序最大的 Tag，结果与列表顺序无关
+//
+// 参数：
+//   - tags: Tag 列表
+//   - includePrerelease: 是否包括先行版本
+//
+// 返回：
+//   - 最高版本的 Tag，没有可选的 Tag 时为空
+func HighestTag(tags []string, includePrerelease bool) string {
+	highestTag := ""
+	var highest SemVer
+	semverFound := false
+	for _, tag := range tags {
+		semver, ok := ParseSemVer(tag)
+		if !ok {
+			continue
+		}
+		semverFound = true
+		if semver.IsPrerelease() && !includePrerelease {
+			continue
+		}
+		if highestTag == "" || semver.Compare(highest) > 0 {
+			highestTag, highest = tag, semver
+		}
+	}
+
+	if !semverFound {
+		for _, tag := range tags {
+			if strings.Compare(tag, highestTag) > 0 {
+				highestTag = tag
+			}
+		}
+	}
+	return highestTag
+}
+
+// IsNewerVersion 判断远端版本是否比本地版本新
+//
+//   - 本地版本为空（未安装）时总是返回 true
+//   - 任一版本不是语义化版本时退化为比较两者是否不同
+//
+// 参数：
+//   - remote: 远端版本
+//   - local: 本地版本
+//
+// 返回：
+//   - 远端版本是否更新
+func IsNewerVersion(remote, local string) bool {
+	if local == "" {
+		return true
+	}
+	remoteSemver, remoteOk := ParseSemVer(remote)
+	localSemver, localOk := ParseSemVer(local)
+	if !remoteOk || !localOk {
+		return remote != local
+	}
+	return remoteSemver.Compare(localSemver) > 0
+}
+
+// compareInt 比较两个整数
+//
+// 参数：
+//   - a: 整数 a
+//   - b: 整数 b
+//
+// 返回：
+//   - a 较小时为 -1，相同时为 0，较大时为 1
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,138 @@
+/*
+File: define_semver_test.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-19 10:12:40
+
+Description: 语义化版本解析和比较的测试
+*/
+
+package general
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseSemVer(t *testing.T) {
+	tests := []struct {
+		version string
+		want    SemVer
+		ok      bool
+	}{
+		{"1.2.3", SemVer{Major: 1, Minor: 2, Patch: 3}, true},
+		{"v1.2.3", SemVer{Major: 1, Minor: 2, Patch: 3}, true},
+		{" v1.2.3 ", SemVer{Major: 1, Minor: 2, Patch: 3}, true},
+		{"v1.2", SemVer{Major: 1, Minor: 2}, true},
+		{"v1", SemVer{Major: 1}, true},
+		{"v1.2.3-rc.1", SemVer{Major: 1, Minor: 2, Patch: 3, Prerelease: []string{"rc", "1"}}, true},
+		{"v1.2.3+build.5", SemVer{Major: 1, Minor: 2, Patch: 3}, true},
+		{"v1.2.3-beta+build.5", SemVer{Major: 1, Minor: 2, Patch: 3, Prerelease: []string{"beta"}}, true},
+		{"v1.2.3-", SemVer{}, false},
+		{"v1.2.3-rc..1", SemVer{}, false},
+		{"v1.2.3.4", SemVer{}, false},
+		{"v1.x.3", SemVer{}, false},
+		{"v-1.2.3", SemVer{}, false},
+		{"latest", SemVer{}, false},
+		{"", SemVer{}, false},
+	}
+	for _, test := range tests {
+		got, ok := ParseSemVer(test.version)
+		if ok != test.ok {
+			t.Errorf("ParseSemVer(%q) ok = %v, want %v", test.version, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if got.Major != test.want.Major || got.Minor != test.want.Minor || got.Patch != test.want.Patch || !slices.Equal(got.Prerelease, test.want.Prerelease) {
+			t.Errorf("ParseSemVer(%q) = %+v, want %+v", test.version, got, test.want)
+		}
+	}
+}
+
+func TestSemVerCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"v1.2", "v1.2.0", 0},
+		{"v1.2.4", "v1.2.3", 1},
+		{"v1.10.0", "v1.9.0", 1},
+		{"v2.0.0", "v1.99.99", 1},
+		{"v1.2.3", "v1.2.3-rc.1", 1},
+		{"v1.2.3-rc.1", "v1.2.2", 1},
+		{"v1.2.3-alpha", "v1.2.3-beta", -1},
+		{"v1.2.3-rc.2", "v1.2.3-rc.10", -1},
+		{"v1.2.3-rc.1", "v1.2.3-rc", 1},
+		{"v1.2.3-1", "v1.2.3-alpha", -1},
+		{"v1.2.3+build.1", "v1.2.3+build.2", 0},
+		{"v1.2.3-rc.1+build.9", "v1.2.3-rc.1", 0},
+	}
+	for _, test := range tests {
+		a, okA := ParseSemVer(test.a)
+		b, okB := ParseSemVer(test.b)
+		if !okA || !okB {
+			t.Fatalf("ParseSemVer(%q, %q) failed", test.a, test.b)
+		}
+		if got := a.Compare(b); got != test.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+		}
+		if got := b.Compare(a); got != -test.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", test.b, test.a, got, -test.want)
+		}
+	}
+}
+
+func TestHighestTag(t *testing.T) {
+	tests := []struct {
+		name              string
+		tags              []string
+		includePrerelease bool
+		want              string
+	}{
+		{"empty", nil, false, ""},
+		{"name order is not version order", []string{"v1.9.0", "v1.10.0", "v1.2.0"}, false, "v1.10.0"},
+		{"stable skips prerelease", []string{"v1.0.0", "v1.1.0-rc.1"}, false, "v1.0.0"},
+		{"prerelease channel", []string{"v1.0.0", "v1.1.0-rc.1"}, true, "v1.1.0-rc.1"},
+		{"release beats its prerelease", []string{"v1.1.0-rc.1", "v1.1.0"}, true, "v1.1.0"},
+		{"only prereleases on stable", []string{"v1.0.0-rc.1", "v1.0.0-rc.2"}, false, ""},
+		{"non-semver tags are ignored", []string{"nightly", "v1.0.0", "latest"}, false, "v1.0.0"},
+		{"build metadata", []string{"v1.0.0+build.2", "v1.0.1+build.1"}, false, "v1.0.1+build.1"},
+		{"no semver tag", []string{"beta", "gamma", "alpha"}, false, "gamma"},
+	}
+	for _, test := range tests {
+		if got := HighestTag(test.tags, test.includePrerelease); got != test.want {
+			t.Errorf("%s: HighestTag(%v, %v) = %q, want %q", test.name, test.tags, test.includePrerelease, got, test.want)
+		}
+		// 结果与列表顺序无关
+		reversed := slices.Clone(test.tags)
+		slices.Reverse(reversed)
+		if got := HighestTag(reversed, test.includePrerelease); got != test.want {
+			t.Errorf("%s: HighestTag(%v, %v) = %q, want %q", test.name, reversed, test.includePrerelease, got, test.want)
+		}
+	}
+}
+
+func TestIsNewerVersion(t *testing.T) {
+	tests := []struct {
+		remote, local string
+		want          bool
+	}{
+		{"v1.0.0", "", true},
+		{"v1.0.1", "v1.0.0", true},
+		{"v1.0.0", "v1.0.0", false},
+		{"v1.0.0", "v1.0.1", false},
+		{"v1.0.0", "v1.0.0-rc.1", true},
+		{"v1.0.0-rc.1", "v1.0.0", false},
+		{"abc123", "def456", true},
+		{"abc123", "abc123", false},
+	}
+	for _, test := range tests {
+		if got := IsNewerVersion(test.remote, test.local); got != test.want {
+			t.Errorf("IsNewerVersion(%q, %q) = %v, want %v", test.remote, test.local, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+/*
+File: define_signature.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-18 05:02:36
+
+Description: 校验 Release 校验文件的签名（minisign、cosign、GPG）
+*/
+
+package general
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"golang.org/x/crypto/blake2b"
+)
+
+// 签名类型
+const (
+	SignatureMinisign = "minisign" // minisign 签名，公钥为 minisign 公钥
+	SignatureCosign   = "cosign"   // cosign sign-blob 使用密钥生成的签名，公钥为 PEM 格式
+	SignatureGpg      = "gpg"      // GPG 分离签名，公钥为 ASCII Armor 格式
+)
+
+// Enabled 是否需要校验签名，要求校验或配置了公钥时校验
+//
+// 返回：
+//   - 是否需要校验签名
+func (s SignatureConfig) Enabled() bool {
+	return s.Required || len(s.PublicKeys) > 0
+}
+
+// FileName 获取签名文件名，未配置时根据签名类型使用默认的文件名
+//
+// 参数：
+//   - checksumsFile: 校验文件名
+//
+// 返回：
+//   - 签名文件名
+func (s SignatureConfig) FileName(checksumsFile string) string {
+	if s.File != "" {
+		return s.File
+	}
+	if strings.ToLower(s.Type) == SignatureMinisign {
+		return checksumsFile + ".minisig"
+	}
+	return checksumsFile + ".sig"
+}
+
+// VerifySignature 使用固定的公钥校验数据的分离签名，任一公钥校验通过即可
+//
+// 参数：
+//   - config: 签名配置项
+//   - data: 被签名的数据
+//   - signature: 签名文件内容
+//
+// 返回：
+//   - 错误信息，校验未通过时不为 nil
+func VerifySignature(config SignatureConfig, data, signature []byte) error {
+	if len(config.PublicKeys) == 0 {
+		return fmt.Errorf("No public key pinned in [program.signature]")
+	}
+
+	var verify func(data, signature []byte, publicKey string) error
+	switch strings.ToLower(config.Type) {
+	case SignatureMinisign:
+		verify = verifyMinisign
+	case SignatureCosign:
+		verify = verifyCosign
+	case SignatureGpg:
+		return verifyGpg(data, signature, config.PublicKeys)
+	default:
+		return fmt.Errorf("Unsupported signature type '%s': only '%s', '%s' and '%s' are supported", config.Type, SignatureMinisign, SignatureCosign, SignatureGpg)
+	}
+
+	failures := make([]string, 0, len(config.PublicKeys))
+	for index, publicKey := range config.PublicKeys {
+		err := verify(data, signature, publicKey)
+		if err == nil {
+			return nil
+		}
+		failures = append(failures, fmt.Sprintf("key %d: %s", index+1, err))
+	}
+	return fmt.Errorf("Signature verification failed (%s)", strings.Join(failures, "; "))
+}
+
+// verifyMinisign 校验 minisign 签名
+//
+//   - 支持旧版的 Ed 签名和默认的 ED（BLAKE2b-512 预哈希）签名，同时校验受信任注释的全局签名
+//
+// 参数：
+//   - data: 被签名的数据
+//   - signature: .minisig 文件内容
+//   - publicKey: minisign 公钥，可以是公钥文件内容或其中的 Base64 行
+//
+// 返回：
+//   - 错误信息
+func verifyMinisign(data, signature []byte, publicKey string) error {
+	// 解析公钥：算法（2 字节）+ 密钥 ID（8 字节）+ Ed25519 公钥（32 字节）
+	keyLine := ""
+	for _, line := range strings.Split(strings.TrimSpace(publicKey), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "untrusted comment:") {
+			keyLine = line
+		}
+	}
+	key, err := base64.StdEncoding.DecodeString(keyLine)
+	if err != nil || len(key) != 42 || string(key[:2]) != "Ed" {
+		return fmt.Errorf("invalid minisign public key")
+	}
+	keyId, edKey := key[2:10], ed25519.PublicKey(key[10:])
+
+	// 解析签名文件：不受信任的注释、签名、受信任的注释、全局签名
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(string(signature)), "\r\n", "\n"), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return fmt.Errorf("invalid minisign signature file")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 74 {
+		return fmt.Errorf("invalid minisign signature")
+	}
+	globalSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return fmt.Errorf("invalid minisign global signature")
+	}
+	if !bytes.Equal(sig[2:10], keyId) {
+		return fmt.Errorf("signed by another key")
+	}
+
+	message := data
+	switch string(sig[:2]) {
+	case "Ed":
+	case "ED":
+		hash := blake2b.Sum512(data)
+		message = hash[:]
+	default:
+		return fmt.Errorf("unsupported minisign signature algorithm")
+	}
+	if !ed25519.Verify(edKey, message, sig[10:]) {
+		return fmt.Errorf("invalid signature")
+	}
+	trustedComment := strings.TrimPrefix(lines[2], "trusted comment: ")
+	if !ed25519.Verify(edKey, append(append([]byte{}, sig[10:]...), trustedComment...), globalSig) {
+		return fmt.Errorf("invalid trusted comment signature")
+	}
+	return nil
+}
+
+// verifyCosign 校验 cosign sign-blob 使用密钥生成的签名
+//
+//   - 只支持密钥签名，不支持无密钥（Fulcio 证书）签名
+//
+// 参数：
+//   - data: 被签名的数据
+//   - signature: 签名文件内容（Base64 编码的签名）
+//   - publicKey: PEM 格式的公钥（ECDSA 或 Ed25519）
+//
+// 返回：
+//   - 错误信息
+func verifyCosign(data, signature []byte, publicKey string) error {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(publicKey)))
+	if block == nil {
+		return fmt.Errorf("invalid PEM public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("invalid PEM public key: %s", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		sig = signature // 未经 Base64 编码的签名
+	}
+
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		hash := sha256.Sum256(data)
+		if !ecdsa.VerifyASN1(key, hash[:], sig) {
+			return fmt.Errorf("invalid signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, data, sig) {
+			return fmt.Errorf("invalid signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+	return nil
+}
+
+// verifyGpg 校验 GPG 分离签名
+//
+// 参数：
+//   - data: 被签名的数据
+//   - signature: 签名文件内容，可以是二进制或 ASCII Armor 格式
+//   - publicKeys: ASCII Armor 格式的公钥
+//
+// 返回：
+//   - 错误信息
+func verifyGpg(data, signature []byte, publicKeys []string) error {
+	var keyring openpgp.EntityList
+	for index, publicKey := range publicKeys {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(publicKey))
+		if err != nil {
+			return fmt.Errorf("Invalid GPG public key %d: %s", index+1, err)
+		}
+		keyring = append(keyring, entities...)
+	}
+
+	var err error
+	if bytes.HasPrefix(bytes.TrimSpace(signature), []byte("-----BEGIN")) {
+		_, err = openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(data), bytes.NewReader(signature), nil)
+	} else {
+		_, err = openpgp.CheckDetachedSignature(keyring, bytes.NewReader(data), bytes.NewReader(signature), nil)
+	}
+	if err != nil {
+		return fmt.Errorf("Signature verification failed: %s", err)
+	}
+	return nil
+}
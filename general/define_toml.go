@@ -25,59 +25,79 @@ type Config struct {
 	Variable VariableConfig `toml:"variable"`
 }
 type ProgramConfig struct {
-	Method        string      `toml:"method"`
-	ProgramPath   string      `toml:"program_path"`
-	ResourcesPath string      `toml:"resources_path"`
-	ReleaseTemp   string      `toml:"release_temp"`
-	SourceTemp    string      `toml:"source_temp"`
-	PocketPath    string      `toml:"pocket_path"`
-	PocketFile    string      `toml:"pocket_file"`
-	Self          SelfConfig  `toml:"self"`
-	Go            GoConfig    `toml:"go"`
-	Shell         ShellConfig `toml:"shell"`
+	Method        string                 `toml:"method"`
+	ProgramPath   string                 `toml:"program_path"`
+	ResourcesPath string                 `toml:"resources_path"`
+	ReleaseTemp   string                 `toml:"release_temp"`
+	SourceTemp    string                 `toml:"source_temp"`
+	PocketPath    string                 `toml:"pocket_path"`
+	CachePath     string                 `toml:"cache_path"`
+	PocketFile    string                 `toml:"pocket_file"`
+	RollbackKeep  int                    `toml:"rollback_keep"`
+	Self          SelfConfig             `toml:"self"`
+	Go            GoConfig               `toml:"go"`
+	Shell         ShellConfig            `toml:"shell"`
+	Signature     SignatureConfig        `toml:"signature"`
+	Builds        map[string]BuildConfig `toml:"builds"`
+	Mirrors       []MirrorConfig         `toml:"mirrors"`
 }
 type VariableConfig struct {
-	HTTPProxy  string `toml:"http_proxy"`
-	HTTPSProxy string `toml:"https_proxy"`
+	HTTPProxy   string `toml:"http_proxy"`
+	HTTPSProxy  string `toml:"https_proxy"`
+	GithubToken string `toml:"github_token"`
+	GiteaToken  string `toml:"gitea_token"`
+	GitlabToken string `toml:"gitlab_token"`
+	RateLimit   string `toml:"rate_limit"`
 }
 type SelfConfig struct {
-	Name           string   `toml:"name"`
-	ReleaseApi     string   `toml:"release_api"`
-	ReleaseAccept  string   `toml:"release_accept"`
-	GeneratePath   string   `toml:"generate_path"`
-	GithubUrl      string   `toml:"github_url"`
-	GithubApi      string   `toml:"github_api"`
-	GithubUsername string   `toml:"github_username"`
-	GiteaUrl       string   `toml:"gitea_url"`
-	GiteaApi       string   `toml:"gitea_api"`
-	GiteaUsername  string   `toml:"gitea_username"`
-	CompletionDir  []string `toml:"completion_dir"`
+	Name          string   `toml:"name"`
+	ReleaseAccept string   `toml:"release_accept"`
+	GeneratePath  string   `toml:"generate_path"`
+	CompletionDir []string `toml:"completion_dir"`
 }
 type GoConfig struct {
-	Names          []string `toml:"names"`
-	ReleaseApi     string   `toml:"release_api"`
-	ReleaseAccept  string   `toml:"release_accept"`
-	GeneratePath   string   `toml:"generate_path"`
-	GithubUrl      string   `toml:"github_url"`
-	GithubApi      string   `toml:"github_api"`
-	GithubUsername string   `toml:"github_username"`
-	GiteaUrl       string   `toml:"gitea_url"`
-	GiteaApi       string   `toml:"gitea_api"`
-	GiteaUsername  string   `toml:"gitea_username"`
-	CompletionDir  []string `toml:"completion_dir"`
+	Names         []string               `toml:"names"`
+	ReleaseAccept string                 `toml:"release_accept"`
+	GeneratePath  string                 `toml:"generate_path"`
+	CompletionDir []string               `toml:"completion_dir"`
+	Channel       string                 `toml:"channel"`
+	Pins          map[string]string      `toml:"pins"`
+	Channels      map[string]string      `toml:"channels"`
+	Assets        map[string]AssetConfig `toml:"assets"`
+}
+type AssetConfig struct {
+	Archive   string `toml:"archive"`
+	Checksums string `toml:"checksums"`
+	Binary    string `toml:"binary"`
+	Libc      string `toml:"libc"`
 }
 type ShellConfig struct {
-	Names          []string `toml:"names"`
-	Repo           string   `toml:"repo"`
-	Dir            string   `toml:"dir"`
-	GithubApi      string   `toml:"github_api"`
-	GithubRaw      string   `toml:"github_raw"`
-	GithubUsername string   `toml:"github_username"`
-	GithubBranch   string   `toml:"github_branch"`
-	GiteaApi       string   `toml:"gitea_api"`
-	GiteaRaw       string   `toml:"gitea_raw"`
-	GiteaUsername  string   `toml:"gitea_username"`
-	GiteaBranch    string   `toml:"gitea_branch"`
+	Names []string `toml:"names"`
+	Repo  string   `toml:"repo"`
+	Dir   string   `toml:"dir"`
+}
+type SignatureConfig struct {
+	Required   bool     `toml:"required"`
+	Type       string   `toml:"type"`
+	File       string   `toml:"file"`
+	PublicKeys []string `toml:"public_keys"`
+}
+type BuildConfig struct {
+	Command []string `toml:"command"`
+	Env     []string `toml:"env"`
+	Tags    []string `toml:"tags"`
+	Ldflags string   `toml:"ldflags"`
+	Output  string   `toml:"output"`
+}
+type MirrorConfig struct {
+	Name      string `toml:"name"`
+	Kind      string `toml:"kind"`
+	Api       string `toml:"api"`
+	Url       string `toml:"url"`
+	Raw       string `toml:"raw"`
+	Username  string `toml:"username"`
+	Branch    string `toml:"branch"`
+	UrlPrefix string `toml:"url_prefix"`
 }
 
 // isTomlFile 检测文件是不是 toml 文件
@@ -129,6 +149,23 @@ func LoadConfigToStruct(configTree *toml.Tree) (*Config, error) {
 	if err := configTree.Unmarshal(&config); err != nil {
 		return nil, err
 	}
+	// 旧配置文件没有 rollback_keep 配置项，使用默认值而不是关闭回滚（显式设置为 0 才关闭）
+	if !configTree.Has("program.rollback_keep") {
+		config.Program.RollbackKeep = rollbackKeep
+	}
+	// 旧配置文件没有镜像列表，使用其中的 github_* 和 gitea_* 配置项
+	if len(config.Program.Mirrors) == 0 {
+		config.Program.Mirrors = legacyMirrors(configTree)
+	}
+	if err := validateChannels(config.Program.Go); err != nil {
+		return nil, err
+	}
+	if err := validateAssets(config.Program.Go); err != nil {
+		return nil, err
+	}
+	if err := validateBuilds(config.Program); err != nil {
+		return nil, err
+	}
 	return &config, nil
 }
 
@@ -28,6 +28,8 @@ var (
 	sourceTemp = filepath.Join(Sep, "tmp", name, "source")
 	// 定义在不同平台的记账文件路径
 	pocketPath = filepath.Join(Sep, "var", "local", "lib", name, "local")
+	// 定义在不同平台的 API 响应缓存路径
+	cachePath = filepath.Join(Sep, "var", "cache", name)
 	// 定义在不同平台可用的程序
 	goNames = []string{
 		name,
@@ -71,7 +73,8 @@ var (
 	// 使用默认值的配置项
 	name           = strings.ToLower(Name)
 	pocketFile     = "files"
-	releaseApi     = "https://api.github.com"
+	rateLimit      = "fallback"
+	rollbackKeep   = 3
 	releaseAccept  = "application/vnd.github+json"
 	generatePath   = "build"
 	githubUrl      = "https://github.com"
@@ -98,49 +101,66 @@ var appConfig = Config{
 		ReleaseTemp:   releaseTemp,
 		SourceTemp:    sourceTemp,
 		PocketPath:    pocketPath,
+		CachePath:     cachePath,
 		PocketFile:    pocketFile,
+		RollbackKeep:  rollbackKeep,
 		Self: SelfConfig{
-			Name:           name,
-			ReleaseApi:     releaseApi,
-			ReleaseAccept:  releaseAccept,
-			GeneratePath:   generatePath,
-			GithubUrl:      githubUrl,
-			GithubApi:      githubApi,
-			GithubUsername: githubUsername,
-			GiteaUrl:       giteaUrl,
-			GiteaApi:       giteaApi,
-			GiteaUsername:  giteaUsername,
-			CompletionDir:  goCompletionDir,
+			Name:          name,
+			ReleaseAccept: releaseAccept,
+			GeneratePath:  generatePath,
+			CompletionDir: goCompletionDir,
 		},
 		Go: GoConfig{
-			Names:          goNames,
-			ReleaseApi:     releaseApi,
-			ReleaseAccept:  releaseAccept,
-			GeneratePath:   generatePath,
-			GithubUrl:      githubUrl,
-			GithubApi:      githubApi,
-			GithubUsername: githubUsername,
-			GiteaUrl:       giteaUrl,
-			GiteaApi:       giteaApi,
-			GiteaUsername:  giteaUsername,
-			CompletionDir:  goCompletionDir,
+			Names:         goNames,
+			ReleaseAccept: releaseAccept,
+			GeneratePath:  generatePath,
+			CompletionDir: goCompletionDir,
+			Channel:       ChannelStable,
+			Pins:          map[string]string{},
+			Channels:      map[string]string{},
+			Assets:        map[string]AssetConfig{},
 		},
 		Shell: ShellConfig{
-			Names:          shellNames,
-			Repo:           repo,
-			Dir:            filepath.Join(localF, localC),
-			GithubApi:      githubApi,
-			GithubRaw:      githubRaw,
-			GithubUsername: githubUsername,
-			GithubBranch:   githubBranch,
-			GiteaApi:       giteaApi,
-			GiteaRaw:       giteaRaw,
-			GiteaUsername:  giteaUsername,
-			GiteaBranch:    giteaBranch,
+			Names: shellNames,
+			Repo:  repo,
+			Dir:   filepath.Join(localF, localC),
+		},
+		Signature: SignatureConfig{
+			Required:   false,
+			Type:       SignatureMinisign,
+			File:       "",
+			PublicKeys: []string{},
+		},
+		Builds: map[string]BuildConfig{},
+		Mirrors: []MirrorConfig{
+			{
+				Name:      "github",
+				Kind:      MirrorKindGithub,
+				Api:       githubApi,
+				Url:       githubUrl,
+				Raw:       githubRaw,
+				Username:  githubUsername,
+				Branch:    githubBranch,
+				UrlPrefix: "",
+			},
+			{
+				Name:      "gitea",
+				Kind:      MirrorKindGitea,
+				Api:       giteaApi,
+				Url:       giteaUrl,
+				Raw:       giteaRaw,
+				Username:  giteaUsername,
+				Branch:    giteaBranch,
+				UrlPrefix: "",
+			},
 		},
 	},
 	Variable: VariableConfig{
-		HTTPProxy:  HttpProxy,
-		HTTPSProxy: HttpsProxy,
+		HTTPProxy:   HttpProxy,
+		HTTPSProxy:  HttpsProxy,
+		GithubToken: "",
+		GiteaToken:  "",
+		GitlabToken: "",
+		RateLimit:   rateLimit,
 	},
 }
@@ -26,6 +26,8 @@ var (
 	sourceTemp = filepath.Join(UserInfo.HomeDir, "AppData", "Local", "Temp", name, "source")
 	// 定义在不同平台的记账文件路径
 	pocketPath = filepath.Join(UserInfo.HomeDir, "AppData", "Local", "Temp", name, "local")
+	// 定义在不同平台的 API 响应缓存路径
+	cachePath = filepath.Join(UserInfo.HomeDir, "AppData", "Local", "Temp", name, "cache")
 	// 定义在不同平台可用的程序
 	goNames = []string{
 		name,
@@ -46,7 +48,8 @@ var (
 	// 使用默认值的配置项
 	name           = strings.ToLower(Name)
 	pocketFile     = "files"
-	releaseApi     = "https://api.github.com"
+	rateLimit      = "fallback"
+	rollbackKeep   = 3
 	releaseAccept  = "application/vnd.github+json"
 	generatePath   = "build"
 	githubUrl      = "https://github.com"
@@ -60,39 +63,60 @@ var (
 // 配置
 var appConfig = Config{
 	Program: ProgramConfig{
-		Method:      InstallMethod,
-		ProgramPath: programPath,
-		ReleaseTemp: releaseTemp,
-		SourceTemp:  sourceTemp,
-		PocketPath:  pocketPath,
-		PocketFile:  pocketFile,
+		Method:       InstallMethod,
+		ProgramPath:  programPath,
+		ReleaseTemp:  releaseTemp,
+		SourceTemp:   sourceTemp,
+		PocketPath:   pocketPath,
+		CachePath:    cachePath,
+		PocketFile:   pocketFile,
+		RollbackKeep: rollbackKeep,
 		Self: SelfConfig{
-			Name:           name,
-			ReleaseApi:     releaseApi,
-			ReleaseAccept:  releaseAccept,
-			GeneratePath:   generatePath,
-			GithubUrl:      githubUrl,
-			GithubApi:      githubApi,
-			GithubUsername: githubUsername,
-			GiteaUrl:       giteaUrl,
-			GiteaApi:       giteaApi,
-			GiteaUsername:  giteaUsername,
+			Name:          name,
+			ReleaseAccept: releaseAccept,
+			GeneratePath:  generatePath,
 		},
 		Go: GoConfig{
-			Names:          goNames,
-			ReleaseApi:     releaseApi,
-			ReleaseAccept:  releaseAccept,
-			GeneratePath:   generatePath,
-			GithubUrl:      githubUrl,
-			GithubApi:      githubApi,
-			GithubUsername: githubUsername,
-			GiteaUrl:       giteaUrl,
-			GiteaApi:       giteaApi,
-			GiteaUsername:  giteaUsername,
+			Names:         goNames,
+			ReleaseAccept: releaseAccept,
+			GeneratePath:  generatePath,
+			Channel:       ChannelStable,
+			Pins:          map[string]string{},
+			Channels:      map[string]string{},
+			Assets:        map[string]AssetConfig{},
+		},
+		Signature: SignatureConfig{
+			Required:   false,
+			Type:       SignatureMinisign,
+			File:       "",
+			PublicKeys: []string{},
+		},
+		Builds: map[string]BuildConfig{},
+		Mirrors: []MirrorConfig{
+			{
+				Name:      "github",
+				Kind:      MirrorKindGithub,
+				Api:       githubApi,
+				Url:       githubUrl,
+				Username:  githubUsername,
+				UrlPrefix: "",
+			},
+			{
+				Name:      "gitea",
+				Kind:      MirrorKindGitea,
+				Api:       giteaApi,
+				Url:       giteaUrl,
+				Username:  giteaUsername,
+				UrlPrefix: "",
+			},
 		},
 	},
 	Variable: VariableConfig{
-		HTTPProxy:  HttpProxy,
-		HTTPSProxy: HttpsProxy,
+		HTTPProxy:   HttpProxy,
+		HTTPSProxy:  HttpsProxy,
+		GithubToken: "",
+		GiteaToken:  "",
+		GitlabToken: "",
+		RateLimit:   rateLimit,
 	},
 }
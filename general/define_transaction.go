@@ -0,0 +1,298 @@
+/*
+File: define_transaction.go
+Author: YJ
+Email: yj1516268@outlook.com
+Created Time: 2026-10-17 21:48:15
+
+Description: 安装事务，记录安装过程中写入的文件，失败时撤销
+*/
+
+package general
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+// 事务日志相关的文件名
+var (
+	transactionDir         = ".transaction" // 事务目录，位于记账文件夹中
+	transactionJournalFile = "journal"      // 事务日志，每行为 '目标文件<Tab>备份文件'，备份文件为空表示目标文件原本不存在
+)
+
+// Transaction 单个程序的安装事务
+type Transaction struct {
+	dir     string   // 事务目录
+	targets []string // 已记录的目标文件
+	backups []string // 目标文件对应的备份文件
+}
+
+// BeginTransaction 开始一个安装事务，事务日志在撤销或提交前一直保留，用于程序崩溃或被中断后恢复
+//
+// 参数：
+//   - pocketPath: 记账文件夹路径
+//   - program: 程序名
+//   - protectedFiles: 事务开始时需要保护的文件，例如会被清空重写的记账文件
+//
+// 返回：
+//   - 安装事务
+//   - 错误信息
+func BeginTransaction(pocketPath, program string, protectedFiles ...string) (*Transaction, error) {
+	dir := filepath.Join(pocketPath, transactionDir, program)
+	// 残留的事务日志说明上次安装未完成，先恢复
+	if FileExist(dir) {
+		if err := (&Transaction{dir: dir}).recover(); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	transaction := &Transaction{dir: dir}
+	for _, file := range protectedFiles {
+		if err := transaction.Track(file); err != nil {
+			transaction.Rollback()
+			return nil, err
+		}
+	}
+
+	return transaction, nil
+}
+
+// Track 记录即将被写入的目标文件，已存在的目标文件会被备份以便撤销
+//
+// 参数：
+//   - targetFile: 目标文件路径
+//
+// 返回：
+//   - 错误信息
+func (t *Transaction) Track(targetFile string) error {
+	if slices.Contains(t.targets, targetFile) {
+		return nil
+	}
+
+	// 备份已存在的目标文件
+	backupFile := ""
+	if info, err := os.Stat(targetFile); err == nil {
+		backupFile = filepath.Join(t.dir, strconv.Itoa(len(t.targets)))
+		if err := Install(targetFile, backupFile, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	// 先写事务日志再修改目标文件
+	journal, err := os.OpenFile(filepath.Join(t.dir, transactionJournalFile), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer journal.Close()
+	if _, err := journal.WriteString(color.Sprintf("%s\t%s\n", targetFile, backupFile)); err != nil {
+		return err
+	}
+	if err := journal.Sync(); err != nil {
+		return err
+	}
+
+	t.targets = append(t.targets, targetFile)
+	t.backups = append(t.backups, backupFile)
+
+	return nil
+}
+
+// Install 在事务中安装文件
+//
+// 参数：
+//   - sourceFile: 源文件路径
+//   - targetFile: 目标文件路径
+//   - perm: 目标文件权限
+//
+// 返回：
+//   - 错误信息
+func (t *Transaction) Install(sourceFile, targetFile string, perm os.FileMode) error {
+	if err := t.Track(targetFile); err != nil {
+		return err
+	}
+	return Install(sourceFile, targetFile, perm)
+}
+
+// MkdirAll 在事务中创建文件夹及其不存在的上级文件夹，撤销时删除新建的最上层文件夹
+//
+// 参数：
+//   - dir: 文件夹路径
+//
+// 返回：
+//   - 错误信息
+func (t *Transaction) MkdirAll(dir string) error {
+	topmost := "" // 不存在的最上层文件夹
+	for current := dir; !FileExist(current); current = filepath.Dir(current) {
+		topmost = current
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+	if topmost == "" {
+		return nil
+	}
+	if err := t.Track(topmost); err != nil {
+		return err
+	}
+	return CreateDir(dir)
+}
+
+// Remove 在事务中删除文件，撤销时从备份恢复
+//
+// 参数：
+//   - targetFile: 目标文件路径
+//
+// 返回：
+//   - 错误信息
+func (t *Transaction) Remove(targetFile string) error {
+	if !FileExist(targetFile) {
+		return nil
+	}
+	if err := t.Track(targetFile); err != nil {
+		return err
+	}
+	return DeleteFile(targetFile)
+}
+
+// Rollback 按相反顺序撤销事务中写入的文件并删除事务日志
+//
+// 返回：
+//   - 错误信息
+func (t *Transaction) Rollback() error {
+	errs := make([]string, 0)
+	for index := len(t.targets) - 1; index >= 0; index-- {
+		if err := undo(t.targets[index], t.backups[index]); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("Rollback incomplete, transaction journal kept in %s: %s", t.dir, strings.Join(errs, "; "))
+	}
+	return DeleteFile(t.dir)
+}
+
+// Commit 提交事务，删除事务日志和备份文件
+//
+// 返回：
+//   - 错误信息
+func (t *Transaction) Commit() error {
+	return DeleteFile(t.dir)
+}
+
+// recover 根据事务日志撤销未完成的事务
+//
+// 返回：
+//   - 错误信息
+func (t *Transaction) recover() error {
+	journal, err := os.Open(filepath.Join(t.dir, transactionJournalFile))
+	if err != nil {
+		if os.IsNotExist(err) { // 事务日志尚未写入，没有需要撤销的文件
+			return DeleteFile(t.dir)
+		}
+		return err
+	}
+	scanner := bufio.NewScanner(journal)
+	for scanner.Scan() {
+		target, backup, found := strings.Cut(scanner.Text(), "\t")
+		if !found || target == "" { // 崩溃时可能写入了不完整的行
+			continue
+		}
+		t.targets = append(t.targets, target)
+		t.backups = append(t.backups, backup)
+	}
+	journal.Close()
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return t.Rollback()
+}
+
+// undo 撤销对单个目标文件的修改
+//
+// 参数：
+//   - targetFile: 目标文件路径
+//   - backupFile: 备份文件路径，为空表示目标文件原本不存在
+//
+// 返回：
+//   - 错误信息
+func undo(targetFile, backupFile string) error {
+	if backupFile == "" {
+		return DeleteFile(targetFile)
+	}
+	info, err := os.Stat(backupFile)
+	if err != nil {
+		return err
+	}
+	return Install(backupFile, targetFile, info.Mode().Perm())
+}
+
+// RecoverTransactions 恢复上次运行时因崩溃或中断而未完成的安装事务
+//
+// 参数：
+//   - pocketPath: 记账文件夹路径
+//
+// 返回：
+//   - 已恢复的程序名
+//   - 错误信息
+func RecoverTransactions(pocketPath string) ([]string, error) {
+	recovered := make([]string, 0)
+
+	root := filepath.Join(pocketPath, transactionDir)
+	if !FileExist(root) {
+		return recovered, nil
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return recovered, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if err := (&Transaction{dir: filepath.Join(root, entry.Name())}).recover(); err != nil {
+			return recovered, err
+		}
+		recovered = append(recovered, entry.Name())
+	}
+
+	return recovered, nil
+}
+
+// PendingTransactions 列出未完成的安装事务，不做任何修改
+//
+// 参数：
+//   - pocketPath: 记账文件夹路径
+//
+// 返回：
+//   - 存在未完成事务的程序名
+//   - 错误信息
+func PendingTransactions(pocketPath string) ([]string, error) {
+	pending := make([]string, 0)
+
+	root := filepath.Join(pocketPath, transactionDir)
+	if !FileExist(root) {
+		return pending, nil
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return pending, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			pending = append(pending, entry.Name())
+		}
+	}
+
+	return pending, nil
+}
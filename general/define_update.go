@@ -10,16 +10,18 @@ Description: 检查程序更新
 package general
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"reflect"
+	"strings"
 	"time"
 )
 
 // RequestApi 请求 API ，返回响应数据
 //
+//   - 请求会携带该主机对应的 Token，并根据响应头 X-RateLimit-* 按限流策略警告、等待或提前失败
+//   - 设置了缓存路径时使用已缓存响应的 ETag/Last-Modified 发送条件请求，响应 304 时返回缓存的数据
+//
 // 参数：
 //   - url: API 地址
 //
@@ -27,6 +29,21 @@ import (
 //   - 响应数据
 //   - 错误信息
 func RequestApi(url string) ([]byte, error) {
+	body, _, err := requestApi(url, rateLimitRetries)
+	return body, err
+}
+
+// requestApi 请求 API ，返回响应数据和下一页的地址，请求数耗尽时按 'wait' 策略最多重试 retries 次
+//
+// 参数：
+//   - url: API 地址
+//   - retries: 剩余的重试次数
+//
+// 返回：
+//   - 响应数据
+//   - 下一页的地址，没有下一页时为空
+//   - 错误信息
+func requestApi(url string, retries int) ([]byte, string, error) {
 	// 创建一个 HTTP 请求客户端
 	client := http.Client{
 		Timeout: 10 * time.Second,
@@ -34,212 +51,107 @@ func RequestApi(url string) ([]byte, error) {
 	// 创建 GET 请求并设置请求头
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	req.Header.Set("Accept", "application/json")
+	authorizeRequest(req)
+	cache := loadApiCache(req)
+
+	// 检查该主机的请求数是否已耗尽
+	if err := checkRateLimit(req.URL.Host); err != nil {
+		return nil, "", err
+	}
+
 	// 发送 HTTP 请求并接收返回值
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// 释放资源
 	defer resp.Body.Close()
 
-	// 检查返回值状态码
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Request failed with status: %s", resp.Status)
-	}
-
-	// 读取并解析响应数据
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to read response body: %v", err)
-	}
-
-	return body, nil
-}
-
-// GetLatestSourceTag 解析 API 响应数据，获取源代码的最新 Tag
-//
-//   - 该函数解析的是 https://api.github.com/repos/{OWNER}/{REPO}/tags 的返回值
-//   - 用于通过 Source 安装程序时获取最新的 Tag
-//
-// 参数：
-//   - body: API 响应数据
-//
-// 返回：
-//   - 最新 Tag
-//   - 错误信息
-func GetLatestSourceTag(body []byte) (string, error) {
-	// 解码 JSON 格式的返回值
-	var datas any
-	if err := json.Unmarshal(body, &datas); err != nil {
-		return "", err
-	}
-
-	// 判断数据类型
-	kind := reflect.ValueOf(datas).Kind()
-
-	if kind == reflect.Slice { // '[{}]' 结构
-		// 判断响应体长度
-		length := len(datas.([]any))
-		if length == 0 {
-			return "", fmt.Errorf("Response body is empty")
+	// 记录限流状态，请求数耗尽导致的失败按限流策略处理
+	exhausted := recordRateLimit(req.URL.Host, resp.Header)
+	if exhausted && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) {
+		state := apiRateLimits[req.URL.Host]
+		if rateLimitPolicy == RateLimitWait && retries > 0 {
+			if err := waitRateLimit(req.URL.Host, state.Reset); err != nil {
+				return nil, "", err
+			}
+			return requestApi(url, retries-1)
 		}
-		// 获取最新版本 Tag ，适用于版本信息和 Tag 信息同步的
-		latestTag := datas.([]any)[0].(map[string]any)["name"].(string)
-		return latestTag, nil
-	} else {
-		return "", fmt.Errorf("Response body has unknown structure")
+		return nil, "", &RateLimitError{Host: req.URL.Host, Reset: state.Reset}
 	}
-}
 
-// GetLatestSourceHash 解析 API 响应体，获取源代码的最新提交的 Hash
-//
-//   - 该函数解析的是 https://api.github.com/repos/{OWNER}/{REPO}/tags 的返回值
-//   - 用于通过 Source 安装不带 Tag 的程序时获取最新提交的 Hash
-//
-// 参数：
-//   - body: API 响应数据
-//
-// 返回：
-//   - 最新提交的 Hash
-//   - 错误信息
-func GetLatestSourceHash(body []byte) (string, error) {
-	// 解码 JSON 格式的返回值
-	var datas any
-	if err := json.Unmarshal(body, &datas); err != nil {
-		return "", err
+	// 远端数据没有变化，使用缓存的数据
+	if resp.StatusCode == http.StatusNotModified && cache != nil {
+		return cache.Body, cache.Next, nil
 	}
 
-	// 判断数据类型
-	kind := reflect.ValueOf(datas).Kind()
-
-	if kind == reflect.Map { // '{}' 结构
-		// 判断响应体长度
-		length := len(datas.(map[string]any))
-		if length == 0 {
-			return "", fmt.Errorf("Response body is empty")
-		}
-		// 获取文件 Hash ，适用于不带外部版本信息的
-		LatestHash := datas.(map[string]any)["sha"].(string)
-		return LatestHash, nil
-	} else {
-		return "", fmt.Errorf("Response body has unknown structure")
+	// 检查返回值状态码
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("Request failed with status: %s", resp.Status)
 	}
-}
 
-// GetLatestReleaseTag 解析 API 响应体，获取 Release 的最新 Tag
-//
-//   - 该函数解析的是 https://api.github.com/repos/{OWNER}/{REPO}/releases/latest 的返回值
-//   - 用于通过 Release 安装程序时获取最新版本的 Tag
-//
-// 参数：
-//   - body: API 响应数据
-//
-// 返回：
-//   - 最新 Release 的 Tag
-//   - 错误信息
-func GetLatestReleaseTag(body []byte) (string, error) {
-	// 解码 JSON 格式的返回值
-	var datas any
-	if err := json.Unmarshal(body, &datas); err != nil {
-		return "", err
+	// 读取并解析响应数据
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed to read response body: %v", err)
 	}
+	next := nextPageUrl(req.URL, resp.Header)
+	saveApiCache(req, resp.Header, body, next)
 
-	// 判断数据类型
-	kind := reflect.ValueOf(datas).Kind()
-
-	if kind == reflect.Map { // '{}' 结构
-		// 判断响应体长度
-		length := len(datas.(map[string]any))
-		if length == 0 {
-			return "", fmt.Errorf("Response body is empty")
-		}
-		// 获取最新 Release 对应的 Tag
-		latestTag := datas.(map[string]any)["tag_name"].(string)
-		return latestTag, nil
-	} else {
-		return "", fmt.Errorf("Response body has unknown structure")
-	}
+	return body, next, nil
 }
 
 // 要获取其信息的文件名
 type FileName struct {
-	ChecksumsFile string `json:"checksums"`
-	ArchiveFile   string `json:"archive"`
+	ChecksumsFile string   `json:"checksums"`
+	ArchiveFiles  []string `json:"archives"` // 可能的压缩包文件名，按优先级排列，使用 Release 中存在的第一个
 }
 
 // 存储多文件信息
 type multipleFilesInfo struct {
-	ChecksumsFileInfo singleFileInfo `json:"checksums_file_info"`
-	ArchiveFileInfo   singleFileInfo `json:"archive_file_info"`
+	ChecksumsFileInfo ReleaseAsset `json:"checksums_file_info"`
+	ArchiveFileInfo   ReleaseAsset `json:"archive_file_info"`
 }
 
-// 存储单文件信息
-type singleFileInfo struct {
-	Name          string  `json:"name"`
-	Size          float64 `json:"size"`
-	ContentType   string  `json:"content_type"`
-	DownloadUrl   string  `json:"download_url"`
-	DownloadCount float64 `json:"download_count"`
-}
-
-// GetReleaseFileInfo 解析 API 响应体，获取 Release 文件的信息
+// GetReleaseFileInfo 从 Release 信息中获取 Release 文件的信息
 //
-//   - 该函数解析的是 https://api.github.com/repos/{OWNER}/{REPO}/releases/latest 的返回值
 //   - 用于通过 Release 安装程序时获取校验文件、压缩包等文件的信息
 //
 // 参数：
-//   - body: API 响应数据
+//   - release: 代码托管平台返回的 Release 信息
 //   - fileName: 要获取其信息的文件名
 //
 // 返回：
 //   - 多文件信息（包括文件名 Name 、文件大小 Size 、文件类型 ContentType 、下载链接 DownloadUrl 和下载次数 DownloadCount）
 //   - 错误信息
-func GetReleaseFileInfo(body []byte, fileName FileName) (multipleFilesInfo, error) {
-	filesInfo := multipleFilesInfo{}      // 存储多文件信息
-	checksumsFileInfo := singleFileInfo{} // 存储校验文件信息
-	archiveFileInfo := singleFileInfo{}   // 存储压缩包信息
-
-	// 解码 JSON 格式的返回值
-	var datas any
-	if err := json.Unmarshal(body, &datas); err != nil {
-		return filesInfo, err
-	}
-
-	// 判断数据类型
-	kind := reflect.ValueOf(datas).Kind()
+func GetReleaseFileInfo(release Release, fileName FileName) (multipleFilesInfo, error) {
+	filesInfo := multipleFilesInfo{} // 存储多文件信息
 
-	if kind == reflect.Map { // '{}' 结构
-		// 判断响应体长度
-		length := len(datas.(map[string]any))
-		if length == 0 {
-			return filesInfo, fmt.Errorf("Response body is empty")
+	if len(release.Assets) == 0 {
+		return filesInfo, fmt.Errorf("Release %s has no assets", release.Tag)
+	}
+	archivePriority := len(fileName.ArchiveFiles)
+	for _, asset := range release.Assets {
+		if asset.Name == fileName.ChecksumsFile {
+			filesInfo.ChecksumsFileInfo = asset
 		}
-		// 获取最新 Release 的 Assets 信息，下载链接等包含在里面
-		assets := datas.(map[string]any)["assets"].([]any)
-		for _, asset := range assets {
-			if asset.(map[string]any)["name"] == fileName.ChecksumsFile {
-				checksumsFileInfo.Name = asset.(map[string]any)["name"].(string)
-				checksumsFileInfo.Size = asset.(map[string]any)["size"].(float64)
-				checksumsFileInfo.ContentType = asset.(map[string]any)["content_type"].(string)
-				checksumsFileInfo.DownloadUrl = asset.(map[string]any)["browser_download_url"].(string)
-				checksumsFileInfo.DownloadCount = asset.(map[string]any)["download_count"].(float64)
-			}
-			if asset.(map[string]any)["name"] == fileName.ArchiveFile {
-				archiveFileInfo.Name = asset.(map[string]any)["name"].(string)
-				archiveFileInfo.Size = asset.(map[string]any)["size"].(float64)
-				archiveFileInfo.ContentType = asset.(map[string]any)["content_type"].(string)
-				archiveFileInfo.DownloadUrl = asset.(map[string]any)["browser_download_url"].(string)
-				archiveFileInfo.DownloadCount = asset.(map[string]any)["download_count"].(float64)
+		for priority, archiveFile := range fileName.ArchiveFiles[:archivePriority] {
+			if asset.Name == archiveFile {
+				filesInfo.ArchiveFileInfo = asset
+				archivePriority = priority
+				break
 			}
 		}
-		filesInfo.ChecksumsFileInfo = checksumsFileInfo
-		filesInfo.ArchiveFileInfo = archiveFileInfo
-		return filesInfo, nil
-	} else {
-		return filesInfo, fmt.Errorf("Response body has unknown structure")
 	}
+	if filesInfo.ChecksumsFileInfo.Name == "" {
+		return filesInfo, fmt.Errorf("Release %s has no asset named %s", release.Tag, fileName.ChecksumsFile)
+	}
+	if filesInfo.ArchiveFileInfo.Name == "" {
+		return filesInfo, fmt.Errorf("Release %s has no asset named any of %s", release.Tag, strings.Join(fileName.ArchiveFiles, ", "))
+	}
+	return filesInfo, nil
 }
@@ -23,6 +23,12 @@ import (
 // 默认延时
 var DelayTime float32 = 0.01
 
+// 有待安装/更新的程序时的退出状态码
+var UpdatesPendingExitCode = 100
+
+// 检查时有程序无法获取远端版本的退出状态码
+var CheckFailedExitCode = 1
+
 // 用来处理不同系统之间的变量名差异
 var platformChart = map[string]map[string]string{
 	"windows": {